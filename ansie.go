@@ -57,15 +57,22 @@ const (
 
 type AnsiBuffer struct {
 	enabled bool
+	profile ColourProfile
 	// ColorCompatibility allows usage of 24-bit colours on terminals that support only 256-colour mode when enabled.
 	ColorCompatibility bool
-	content            strings.Builder
+	// SgrOptimisation enables optimisation of SGR sequences in the strings returned by String, see OptimiseSgr.
+	// The colours and attributes in effect at the end of the string are remembered, so that the next string
+	// continues from the same state
+	SgrOptimisation bool
+	content         strings.Builder
+	// sgrState is the style in effect after the last string returned with SgrOptimisation enabled
+	sgrState Style
 }
 
 // NewAnsi creates a new AnsiBuffer. It doesn't assume anything about the device that the output will be
 // directed to.
 func NewAnsi() *AnsiBuffer {
-	return &AnsiBuffer{enabled: true, ColorCompatibility: false}
+	return &AnsiBuffer{enabled: true, profile: TrueColour, ColorCompatibility: false}
 }
 
 // Ansi is a default instance of AnsiBuffer
@@ -80,7 +87,7 @@ func NewAnsiFor(f *os.File) *AnsiBuffer {
 		panic(err)
 	}
 	enabled := (o.Mode() & os.ModeCharDevice) == os.ModeCharDevice
-	return &AnsiBuffer{enabled: enabled, ColorCompatibility: false}
+	return &AnsiBuffer{enabled: enabled, profile: TrueColour, ColorCompatibility: false}
 }
 
 func (ap *AnsiBuffer) CursorLeft(count int) *AnsiBuffer {
@@ -129,6 +136,11 @@ func (ap *AnsiBuffer) SetEnabled(value bool) {
 func (ap *AnsiBuffer) String() string {
 	s := ap.content.String()
 	ap.Clear()
+	if ap.SgrOptimisation && ap.enabled {
+		o := sgrOptimiser{state: ap.sgrState, compat: ap.ColorCompatibility}
+		s = o.optimise(s)
+		ap.sgrState = o.state
+	}
 	return s
 }
 
@@ -144,11 +156,7 @@ func (ap *AnsiBuffer) Reset() *AnsiBuffer {
 // To use 24-bit colour with 256-colour terminals, use FgRgb6 method or Rgb6x6x6 function to convert RGB values
 // to 256-colour code.
 func (ap *AnsiBuffer) Fg(colour Colour) *AnsiBuffer {
-	if colour < 8 {
-		ap.writeAnsiSeq(30 + colour)
-	} else {
-		ap.writeAnsiSeq(38, 5, colour)
-	}
+	ap.writeColour(30, colour)
 	return ap
 }
 
@@ -158,11 +166,7 @@ func (ap *AnsiBuffer) Fg(colour Colour) *AnsiBuffer {
 // To use 24-bit colour with 256-colour terminals, use BgRgb6 method or Rgb6x6x6 function to convert RGB values
 // to 256-colour code.
 func (ap *AnsiBuffer) Bg(colour Colour) *AnsiBuffer {
-	if colour < 8 {
-		ap.writeAnsiSeq(40 + colour)
-	} else {
-		ap.writeAnsiSeq(48, 5, colour)
-	}
+	ap.writeColour(40, colour)
 	return ap
 }
 
@@ -171,7 +175,9 @@ func (ap *AnsiBuffer) Bg(colour Colour) *AnsiBuffer {
 // If used with one of 256 colour codes, it will just set the colour, without modifying the intensity
 func (ap *AnsiBuffer) FgHi(colour Colour) *AnsiBuffer {
 	if colour <= 7 {
-		ap.writeAnsiSeq(90 + colour)
+		if ap.profile != NoColour {
+			ap.writeAnsiSeq(90 + colour)
+		}
 		return ap
 	} else {
 		return ap.Fg(colour)
@@ -189,7 +195,9 @@ func (ap *AnsiBuffer) Attr(attr Attribute) *AnsiBuffer {
 // If used with one of 256 colour codes, it will just set the colour, without modifying the intensity
 func (ap *AnsiBuffer) BgHi(colour Colour) *AnsiBuffer {
 	if colour <= 7 {
-		ap.writeAnsiSeq(100 + colour)
+		if ap.profile != NoColour {
+			ap.writeAnsiSeq(100 + colour)
+		}
 		return ap
 	} else {
 		return ap.Bg(colour)
@@ -198,10 +206,7 @@ func (ap *AnsiBuffer) BgHi(colour Colour) *AnsiBuffer {
 
 // FgRgb sets foreground colour using "true colour" RGB colour
 func (ap *AnsiBuffer) FgRgb(r, g, b uint) *AnsiBuffer {
-	if ap.ColorCompatibility {
-		ap.Fg(Rgb6x6x6(r, g, b))
-	}
-	ap.writeAnsiSeq(38, 2, int(clip(r, 255)), int(clip(g, 255)), int(clip(b, 255)))
+	ap.writeRgbColour(30, r, g, b)
 	return ap
 }
 
@@ -210,19 +215,13 @@ func (ap *AnsiBuffer) FgRgbI(i uint) *AnsiBuffer {
 	r := (i >> 16) & 0xFF
 	g := (i >> 8) & 0xFF
 	b := i & 0xFF
-	if ap.ColorCompatibility {
-		ap.Fg(Rgb6x6x6(r, g, b))
-	}
-	ap.writeAnsiSeq(38, 2, int(clip(r, 255)), int(clip(g, 255)), int(clip(b, 255)))
+	ap.writeRgbColour(30, r, g, b)
 	return ap
 }
 
 // BgRgb sets foreground colour using "true colour" RGB colour
 func (ap *AnsiBuffer) BgRgb(r, g, b uint) *AnsiBuffer {
-	if ap.ColorCompatibility {
-		ap.Bg(Rgb6x6x6(r, g, b))
-	}
-	ap.writeAnsiSeq(48, 2, int(clip(r, 255)), int(clip(g, 255)), int(clip(b, 255)))
+	ap.writeRgbColour(40, r, g, b)
 	return ap
 }
 
@@ -231,10 +230,7 @@ func (ap *AnsiBuffer) BgRgbI(i uint) *AnsiBuffer {
 	r := (i >> 16) & 0xFF
 	g := (i >> 8) & 0xFF
 	b := i & 0xFF
-	if ap.ColorCompatibility {
-		ap.Bg(Rgb6x6x6(r, g, b))
-	}
-	ap.writeAnsiSeq(48, 2, int(clip(r, 255)), int(clip(g, 255)), int(clip(b, 255)))
+	ap.writeRgbColour(40, r, g, b)
 	return ap
 }
 
@@ -384,6 +380,57 @@ func (ap *AnsiBuffer) writeAnsiSeq(codes ...int) {
 	ap.writeAnsiCommand('m', ';', codes...)
 }
 
+// writeColour writes a palette colour converting it to the closest colour supported by the profile.
+// base is 30 for the foreground and 40 for the background colour
+func (ap *AnsiBuffer) writeColour(base int, colour Colour) {
+	if codes := ap.paletteColourCodes(base, colour); len(codes) > 0 {
+		ap.writeAnsiSeq(codes...)
+	}
+}
+
+// writeRgbColour writes 24-bit colour converting it to the closest colour supported by the profile
+// base is 30 for the foreground and 40 for the background colour
+func (ap *AnsiBuffer) writeRgbColour(base int, r, g, b uint) {
+	if ap.profile == TrueColour && ap.ColorCompatibility {
+		ap.writeColour(base, Rgb6x6x6(r, g, b))
+	}
+	if codes := ap.rgbColourCodes(base, r, g, b); len(codes) > 0 {
+		ap.writeAnsiSeq(codes...)
+	}
+}
+
+func (ap *AnsiBuffer) paletteColourCodes(base int, colour Colour) []int {
+	switch {
+	case ap.profile == NoColour:
+		return nil
+	case colour < 8:
+		return []int{base + colour}
+	case ap.profile == Colours16:
+		if colour >= 16 {
+			colour = Rgb16(paletteRgb(colour))
+		}
+		if colour < 8 {
+			return []int{base + colour}
+		}
+		return []int{base + 60 + colour - 8}
+	default:
+		return []int{base + 8, 5, colour}
+	}
+}
+
+func (ap *AnsiBuffer) rgbColourCodes(base int, r, g, b uint) []int {
+	switch ap.profile {
+	case NoColour:
+		return nil
+	case Colours16:
+		return ap.paletteColourCodes(base, Rgb16(r, g, b))
+	case Colours256:
+		return ap.paletteColourCodes(base, Rgb6x6x6(r, g, b))
+	default:
+		return []int{base + 8, 2, int(clip(r, 255)), int(clip(g, 255)), int(clip(b, 255))}
+	}
+}
+
 func clip(c uint, high uint) uint {
 	if c > high {
 		return high
@@ -410,3 +457,9 @@ func (ap *AnsiBuffer) shadeOfGrayColour(intensity float64) int {
 	gray := 232 + int(23*intensity)
 	return gray
 }
+
+// scratch creates an empty AnsiBuffer with the same settings, used to render fragments that are measured or
+// truncated before they are added to this buffer
+func (ap *AnsiBuffer) scratch() *AnsiBuffer {
+	return &AnsiBuffer{enabled: ap.enabled, profile: ap.profile, ColorCompatibility: ap.ColorCompatibility}
+}
@@ -5,6 +5,8 @@
 package ansie
 
 import (
+	"image"
+	"image/color"
 	"log"
 	"os"
 	"testing"
@@ -330,3 +332,13 @@ func TestClearEol(t *testing.T) {
 	s := a.A("\r").ClearEol().Fg(Fuchsia).A("text").String()
 	g.Expect(s).To(Equal("\r\033[K\033[38;5;13mtext"))
 }
+
+func testImage(w, h int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 255 / w), G: uint8(y * 255 / h), B: 128, A: 255})
+		}
+	}
+	return img
+}
@@ -0,0 +1,298 @@
+package ansie
+
+import (
+	"math"
+)
+
+const (
+	defaultChartWidth     = 60
+	defaultBarChartHeight = 10
+	defaultBarWidth       = 3
+)
+
+// BarSeries is a named series of values shown in the BarChart. The values of several series are stacked
+// on top of each other. Negative, NaN and infinite values are treated as zero.
+type BarSeries struct {
+	Name   string
+	Values []float64
+	Style  Style
+}
+
+// BarChart renders horizontal or vertical bar charts with labels. Bars are drawn with eighth-block characters,
+// so their length is shown with the precision of 1/8 of a cell. When colours are disabled, the stacked series
+// are drawn with different fill patterns.
+type BarChart struct {
+	// Labels of the bars. The number of bars is the number of labels or the longest series, whichever is larger
+	Labels []string
+	// Series of values. Values with the same index in all series are stacked into a single bar
+	Series []BarSeries
+	// Vertical draws bars from the bottom up with labels under them
+	Vertical bool
+	// Width of the chart in cells. Defaults to 60 for horizontal charts. Vertical charts are as wide as their bars,
+	// if Width is set, the bars are narrowed to fit it. If they still don't fit, the bars on the right are dropped
+	// and the last column shows "…"
+	Width int
+	// Height of vertical chart in cells, including the labels and the legend. Defaults to 10
+	Height int
+	// BarWidth is the width of each bar in vertical charts. Defaults to 3
+	BarWidth int
+	// Max is the value corresponding to the full length of the bar. If zero, the largest bar is used
+	Max float64
+	// ShowValues adds the total value of each bar to the chart
+	ShowValues bool
+	// ValueFormat is fmt format of the values, "%g" by default
+	ValueFormat string
+	// LabelStyle is the style of the labels and values
+	LabelStyle Style
+}
+
+// barSegment is a part of the stacked bar, measured in eighths of the cell
+type barSegment struct {
+	end   int
+	style Style
+	fill  rune
+}
+
+// Render adds the chart to the AnsiBuffer's buffer. Every line except the last one is terminated with a line break.
+// If there is more than one series, a legend with the series names is added below the chart.
+func (c *BarChart) Render(a *AnsiBuffer) *AnsiBuffer {
+	bars := len(c.Labels)
+	for _, s := range c.Series {
+		bars = max(bars, len(s.Values))
+	}
+	if bars == 0 {
+		return a
+	}
+	totals := make([]float64, bars)
+	scaleMax := c.Max
+	autoScale := !(c.Max > 0) || math.IsInf(c.Max, 0)
+	if autoScale {
+		scaleMax = 0
+	}
+	for i := range totals {
+		for _, s := range c.Series {
+			totals[i] += s.value(i)
+		}
+		if autoScale {
+			scaleMax = math.Max(scaleMax, totals[i])
+		}
+	}
+	if c.Vertical {
+		c.renderVertical(a, totals, scaleMax)
+	} else {
+		c.renderHorizontal(a, totals, scaleMax)
+	}
+	if len(c.Series) > 1 {
+		a.CR()
+		names := make([]string, len(c.Series))
+		styles := make([]Style, len(c.Series))
+		for i, s := range c.Series {
+			names[i] = s.Name
+			styles[i] = s.Style
+		}
+		renderLegend(a, names, styles, func(i int) rune { return c.fill(a, i) })
+	}
+	return a
+}
+
+func (c *BarChart) renderHorizontal(a *AnsiBuffer, totals []float64, scaleMax float64) {
+	width := c.Width
+	if width <= 0 {
+		width = defaultChartWidth
+	}
+	labelWidth := 0
+	for _, label := range c.Labels {
+		labelWidth = max(labelWidth, StringWidth(label))
+	}
+	labelWidth = min(labelWidth, width/3)
+	valueWidth := 0
+	if c.ShowValues {
+		for _, total := range totals {
+			valueWidth = max(valueWidth, StringWidth(formatValue(c.ValueFormat, total)))
+		}
+	}
+	barArea := width - valueWidth
+	if labelWidth > 0 {
+		barArea -= labelWidth + 1
+	}
+	if valueWidth > 0 {
+		barArea--
+	}
+	barArea = max(barArea, 1)
+	for i, total := range totals {
+		if i > 0 {
+			a.CR()
+		}
+		if labelWidth > 0 {
+			label := ""
+			if i < len(c.Labels) {
+				label = Truncate(c.Labels[i], labelWidth, "…")
+			}
+			a.WithStyle(c.LabelStyle, Align(label, labelWidth, AlignLeft)).A(" ")
+		}
+		segments := c.segments(a, i, barArea, scaleMax)
+		cells := blankCells(barArea)
+		for cell := range cells {
+			for _, seg := range segments {
+				if seg.end >= (cell+1)*8 {
+					cells[cell] = chartCell{r: seg.fill, style: seg.style}
+					break
+				}
+				if seg.end > cell*8 {
+					cells[cell] = chartCell{r: horizontalEighths[seg.end-cell*8-1], style: seg.style}
+					break
+				}
+			}
+		}
+		if c.ShowValues {
+			end := 0
+			if len(segments) > 0 {
+				end = (segments[len(segments)-1].end + 7) / 8
+			}
+			renderCells(a, cells[:min(end, len(cells))])
+			a.A(" ").WithStyle(c.LabelStyle, formatValue(c.ValueFormat, total))
+		} else {
+			renderCells(a, cells)
+		}
+	}
+}
+
+func (c *BarChart) renderVertical(a *AnsiBuffer, totals []float64, scaleMax float64) {
+	height := c.Height
+	if height <= 0 {
+		height = defaultBarChartHeight
+	}
+	barWidth := c.BarWidth
+	if barWidth <= 0 {
+		barWidth = defaultBarWidth
+	}
+	plotHeight := height
+	if len(c.Labels) > 0 {
+		plotHeight--
+	}
+	if len(c.Series) > 1 {
+		plotHeight--
+	}
+	barArea := plotHeight
+	if c.ShowValues {
+		barArea--
+	}
+	barArea = max(barArea, 1)
+	plotHeight = max(plotHeight, barArea)
+	barWidth, gap, bars := c.fitBars(len(totals), barWidth)
+	width := bars*(barWidth+gap) - gap
+	truncated := bars < len(totals)
+	if truncated {
+		width = c.Width
+	}
+	grid := make([][]chartCell, plotHeight)
+	for row := range grid {
+		grid[row] = blankCells(width)
+	}
+	for i, total := range totals[:bars] {
+		left := i * (barWidth + gap)
+		segments := c.segments(a, i, barArea, scaleMax)
+		top := plotHeight
+		for level := 0; level < barArea; level++ {
+			row := plotHeight - 1 - level
+			for _, seg := range segments {
+				var r rune
+				if seg.end >= (level+1)*8 {
+					r = seg.fill
+				} else if seg.end > level*8 {
+					r = verticalEighths[seg.end-level*8-1]
+				} else {
+					continue
+				}
+				for x := left; x < left+barWidth; x++ {
+					grid[row][x] = chartCell{r: r, style: seg.style}
+				}
+				top = row
+				break
+			}
+		}
+		if c.ShowValues {
+			value := formatValue(c.ValueFormat, total)
+			pos := left + (barWidth-StringWidth(value))/2
+			putText(grid[max(top-1, 0)], max(pos, left), Truncate(value, barWidth, ""), c.LabelStyle)
+		}
+	}
+	if truncated {
+		grid[plotHeight-1][width-1] = chartCell{r: '…', style: c.LabelStyle}
+	}
+	for row, cells := range grid {
+		if row > 0 {
+			a.CR()
+		}
+		renderCells(a, cells)
+	}
+	if len(c.Labels) > 0 {
+		labels := blankCells(width)
+		for i, label := range c.Labels[:min(bars, len(c.Labels))] {
+			label = Truncate(label, barWidth, "")
+			putText(labels, i*(barWidth+gap), Align(label, barWidth, AlignCenter), c.LabelStyle)
+		}
+		a.CR()
+		renderCells(a, labels)
+	}
+}
+
+// fitBars fits the bars of the vertical chart into Width. It returns the width of the bars, the gap between them
+// and the number of bars that fit. The bars are narrowed first, then the gaps are removed, and then the bars
+// that don't fit are dropped leaving one column for the truncation mark
+func (c *BarChart) fitBars(n int, barWidth int) (int, int, int) {
+	if c.Width <= 0 || n*(barWidth+1)-1 <= c.Width {
+		return barWidth, 1, n
+	}
+	if fitted := (c.Width+1)/n - 1; fitted >= 1 {
+		return fitted, 1, n
+	}
+	if n <= c.Width {
+		return 1, 0, n
+	}
+	return 1, 0, max(c.Width-1, 0)
+}
+
+// segments calculates the stacked segments of the bar with index i scaled to the length of the bar area.
+// Only the end of the last segment has sub-cell precision, the boundaries between segments are rounded to
+// whole cells, as a cell can't show two colours of foreground
+func (c *BarChart) segments(a *AnsiBuffer, i int, barArea int, scaleMax float64) []barSegment {
+	if scaleMax <= 0 {
+		return nil
+	}
+	var segments []barSegment
+	cumulative := 0.0
+	last := -1
+	for k, s := range c.Series {
+		if s.value(i) > 0 {
+			last = k
+		}
+	}
+	for k, s := range c.Series {
+		if s.value(i) <= 0 {
+			continue
+		}
+		cumulative += s.value(i)
+		end := int(math.Round(math.Min(cumulative/scaleMax, 1) * float64(barArea*8)))
+		if k != last || c.fill(a, k) != monochromePatterns[0] {
+			end = (end + 4) / 8 * 8
+		}
+		segments = append(segments, barSegment{end: end, style: s.Style, fill: c.fill(a, k)})
+	}
+	return segments
+}
+
+// value returns i-th value of the series, missing, negative and non-finite values are zero
+func (s BarSeries) value(i int) float64 {
+	if i >= len(s.Values) || !(s.Values[i] > 0) || math.IsInf(s.Values[i], 1) {
+		return 0
+	}
+	return s.Values[i]
+}
+
+func (c *BarChart) fill(a *AnsiBuffer, series int) rune {
+	if isMonochrome(a) {
+		return monochromePatterns[series%len(monochromePatterns)]
+	}
+	return monochromePatterns[0]
+}
@@ -0,0 +1,140 @@
+package ansie
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestBarChart_Horizontal(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	c := &BarChart{
+		Labels: []string{"one", "two"},
+		Series: []BarSeries{{Values: []float64{2, 4}}},
+		Width:  10,
+	}
+	g.Expect(c.Render(a).String()).To(Equal("one ███   \ntwo ██████"))
+
+	c.Series[0].Values = []float64{1, 3}
+	c.Max = 2
+	g.Expect(c.Render(a).String()).To(Equal("one ███   \ntwo ██████"), "Expected bars to be clipped to Max")
+
+	c.Max = 0
+	c.Series[0].Values = []float64{1.25, 4}
+	g.Expect(c.Render(a).String()).To(Equal("one █▉    \ntwo ██████"), "Expected partial cells to use eighth blocks")
+
+	c.Labels[1] = "three"
+	g.Expect(c.Render(a).String()).To(HaveSuffix("\nth… ██████"), "Expected labels to be truncated to a third of the width")
+}
+
+func TestBarChart_NonFiniteValues(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	c := &BarChart{
+		Labels: []string{"a", "b", "c", "d"},
+		Series: []BarSeries{{Values: []float64{math.NaN(), 4, math.Inf(1), math.Inf(-1)}}},
+		Width:  6,
+	}
+	g.Expect(c.Render(a).String()).To(Equal("a     \nb ████\nc     \nd     "), "Expected non-finite values to be zero")
+
+	c.Max = math.NaN()
+	g.Expect(c.Render(a).String()).To(Equal("a     \nb ████\nc     \nd     "), "Expected NaN Max to be ignored")
+}
+
+func TestBarChart_HorizontalValues(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	c := &BarChart{
+		Labels:     []string{"a", "b"},
+		Series:     []BarSeries{{Values: []float64{1, 2}, Style: NewStyle().Fg(Red)}},
+		Width:      8,
+		ShowValues: true,
+	}
+	g.Expect(c.Render(a).String()).To(Equal("a \033[31m██\033[0m 1\nb \033[31m████\033[0m 2"))
+}
+
+func TestBarChart_Stacked(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	c := &BarChart{
+		Series: []BarSeries{
+			{Name: "x", Values: []float64{1, 2}, Style: NewStyle().Fg(Red)},
+			{Name: "y", Values: []float64{1, 0}, Style: NewStyle().Fg(Blue)},
+		},
+		Width: 4,
+	}
+	g.Expect(c.Render(NewAnsi()).String()).To(Equal(
+		"\033[31m██\033[0m\033[34m██\033[0m\n\033[31m████\033[0m\n\033[31m█\033[0m x  \033[34m█\033[0m y"))
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	g.Expect(c.Render(a).String()).To(Equal("██▓▓\n████\n█ x  ▓ y"), "Expected patterns to distinguish series without colours")
+}
+
+func TestBarChart_Vertical(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	c := &BarChart{
+		Labels:     []string{"a", "bbbb"},
+		Series:     []BarSeries{{Values: []float64{1, 4}}},
+		Vertical:   true,
+		Height:     5,
+		BarWidth:   2,
+		ShowValues: true,
+	}
+	lines := strings.Split(c.Render(a).String(), "\n")
+	g.Expect(lines).To(Equal([]string{
+		"   4 ",
+		"   ██",
+		"1  ██",
+		"▆▆ ██",
+		"a  bb",
+	}))
+	c.Series[0].Values = []float64{1.5, 4}
+	c.ShowValues = false
+	lines = strings.Split(c.Render(a).String(), "\n")
+	g.Expect(lines[2:4]).To(Equal([]string{"▄▄ ██", "██ ██"}))
+}
+
+func TestBarChart_VerticalWidth(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	c := &BarChart{
+		Labels:   []string{"a", "b", "c"},
+		Series:   []BarSeries{{Values: []float64{1, 2, 2}}},
+		Vertical: true,
+		Height:   3,
+		Width:    7,
+	}
+	g.Expect(strings.Split(c.Render(a).String(), "\n")).To(Equal([]string{
+		"  █ █",
+		"█ █ █",
+		"a b c",
+	}), "Expected bars to be narrowed")
+
+	c.Width = 3
+	g.Expect(strings.Split(c.Render(a).String(), "\n")).To(Equal([]string{
+		" ██",
+		"███",
+		"abc",
+	}), "Expected gaps to be removed")
+
+	c.Width = 2
+	g.Expect(strings.Split(c.Render(a).String(), "\n")).To(Equal([]string{
+		"  ",
+		"█…",
+		"a ",
+	}), "Expected truncated bars to be marked")
+}
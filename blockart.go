@@ -0,0 +1,363 @@
+package ansie
+
+import (
+	"image"
+	"math"
+)
+
+// BlockMode defines the characters used to render an image as text
+type BlockMode int
+
+const (
+	// HalfBlocks renders two pixels per character cell using '▀' with foreground and background colours
+	HalfBlocks BlockMode = iota
+	// Quadrants renders 2x2 pixels per character cell using quadrant block characters. Each cell has two colours.
+	Quadrants
+	// Sextants renders 2x3 pixels per character cell using sextant characters from Unicode 13
+	// "Symbols for Legacy Computing" block. Each cell has two colours. Not all fonts support these characters.
+	Sextants
+	// Braille renders monochrome image with 2x4 pixels per character cell using braille patterns
+	Braille
+)
+
+const defaultThreshold = 0.5
+
+// quadrant characters indexed by the bit mask of lit pixels: top-left = 1, top-right = 2,
+// bottom-left = 4, bottom-right = 8
+var quadrantRunes = [16]rune{' ', '▘', '▝', '▀', '▖', '▌', '▞', '▛', '▗', '▚', '▐', '▜', '▄', '▙', '▟', '█'}
+
+// braille dot bits indexed by [y][x] position of the dot within the 2x4 cell
+var brailleDots = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+const brailleBase = 0x2800
+
+// ImageRenderer converts images into text using block or braille characters. It is useful as a fallback
+// for terminals that don't support any graphics protocol.
+type ImageRenderer struct {
+	// Mode defines the characters used to render the image
+	Mode BlockMode
+	// Width and Height of the rendered image in character cells. If both are zero, every pixel of the image is
+	// mapped to one sub-cell pixel. If one of them is zero, it is calculated from the other preserving the aspect
+	// ratio of the image.
+	Width  int
+	Height int
+	// Dither enables Floyd–Steinberg dithering when reducing the image to the colours available in the colour
+	// profile or to two levels for the monochrome output
+	Dither bool
+	// Threshold is the brightness in the range of [0..1] above which the pixel is lit in the monochrome output.
+	// Zero value means 0.5
+	Threshold float64
+}
+
+type rgbF struct {
+	r, g, b float64
+}
+
+// Render renders the image into AnsiBuffer's buffer. Colours are chosen according to the profile of the buffer.
+// Braille mode, disabled buffer or NoColour profile produce monochrome output without colours.
+// Every row of the image is terminated with a line break, except the last one.
+func (r *ImageRenderer) Render(a *AnsiBuffer, img image.Image) *AnsiBuffer {
+	cellW, cellH := r.cellPixels()
+	cols, rows := r.size(img.Bounds(), cellW, cellH)
+	if cols <= 0 || rows <= 0 {
+		return a
+	}
+	w := cols * cellW
+	h := rows * cellH
+	pixels := resample(img, w, h)
+	monochrome := r.Mode == Braille || !a.IsEnabled() || a.Profile() == NoColour
+	if r.Dither {
+		ditherFloydSteinberg(pixels, w, h, r.quantizer(a.Profile(), monochrome))
+	}
+	cell := make([]rgbF, cellW*cellH)
+	for row := 0; row < rows; row++ {
+		var lastFg, lastBg = -1, -1
+		for col := 0; col < cols; col++ {
+			for y := 0; y < cellH; y++ {
+				for x := 0; x < cellW; x++ {
+					cell[y*cellW+x] = pixels[(row*cellH+y)*w+col*cellW+x]
+				}
+			}
+			if monochrome {
+				a.A(string(r.glyph(r.monochromeMask(cell))))
+				continue
+			}
+			mask, fg, bg := fitTwoColours(cell)
+			fullMask := 1<<len(cell) - 1
+			if mask == fullMask {
+				mask, bg = 0, fg
+			}
+			if mask != 0 {
+				lastFg = r.writeColour(a, 30, fg, lastFg)
+			}
+			lastBg = r.writeColour(a, 40, bg, lastBg)
+			a.A(string(r.glyph(mask)))
+		}
+		if !monochrome {
+			a.Reset()
+		}
+		if row < rows-1 {
+			a.CR()
+		}
+	}
+	return a
+}
+
+func (r *ImageRenderer) cellPixels() (int, int) {
+	switch r.Mode {
+	case Quadrants:
+		return 2, 2
+	case Sextants:
+		return 2, 3
+	case Braille:
+		return 2, 4
+	default:
+		return 1, 2
+	}
+}
+
+// size calculates the size of the rendered image in cells. Character cells are assumed to be twice as high as wide
+func (r *ImageRenderer) size(bounds image.Rectangle, cellW, cellH int) (int, int) {
+	imgW := float64(bounds.Dx())
+	imgH := float64(bounds.Dy())
+	if imgW == 0 || imgH == 0 {
+		return 0, 0
+	}
+	pixelAspect := 2 * float64(cellW) / float64(cellH)
+	cols, rows := r.Width, r.Height
+	switch {
+	case cols == 0 && rows == 0:
+		cols = int(math.Ceil(imgW / float64(cellW)))
+		rows = int(math.Ceil(imgH / float64(cellH)))
+	case rows == 0:
+		pixelsHigh := imgH * float64(cols*cellW) / imgW / pixelAspect
+		rows = max(1, int(math.Round(pixelsHigh/float64(cellH))))
+	case cols == 0:
+		pixelsWide := imgW * float64(rows*cellH) / imgH * pixelAspect
+		cols = max(1, int(math.Round(pixelsWide/float64(cellW))))
+	}
+	return cols, rows
+}
+
+func (r *ImageRenderer) threshold() float64 {
+	if r.Threshold <= 0 {
+		return defaultThreshold
+	}
+	return r.Threshold
+}
+
+func (r *ImageRenderer) monochromeMask(cell []rgbF) int {
+	mask := 0
+	for i, p := range cell {
+		if luminance(p) > r.threshold() {
+			mask |= 1 << i
+		}
+	}
+	return mask
+}
+
+// glyph returns the character for the bit mask of lit pixels, where bit i corresponds to i-th pixel
+// of the cell counting left to right and top to bottom
+func (r *ImageRenderer) glyph(mask int) rune {
+	switch r.Mode {
+	case Quadrants:
+		return quadrantRunes[mask]
+	case Sextants:
+		return sextantRune(mask)
+	case Braille:
+		var dots rune
+		for i := 0; i < 8; i++ {
+			if mask&(1<<i) != 0 {
+				dots |= brailleDots[i/2][i%2]
+			}
+		}
+		return brailleBase + dots
+	default:
+		return []rune{' ', '▀', '▄', '█'}[mask]
+	}
+}
+
+// writeColour writes the colour unless it is the same as the last colour written and returns the key of the colour
+func (r *ImageRenderer) writeColour(a *AnsiBuffer, base int, c rgbF, last int) int {
+	red, green, blue := uint(math.Round(c.r)), uint(math.Round(c.g)), uint(math.Round(c.b))
+	var key int
+	switch a.Profile() {
+	case Colours16:
+		key = nearestPaletteColour(c.r, c.g, c.b, 0, 16)
+	case Colours256:
+		key = nearestPaletteColour(c.r, c.g, c.b, 16, 256)
+	default:
+		key = int(red<<16 | green<<8 | blue)
+	}
+	if key == last {
+		return last
+	}
+	if a.Profile() == TrueColour {
+		a.writeRgbColour(base, red, green, blue)
+	} else {
+		a.writeColour(base, key)
+	}
+	return key
+}
+
+// quantizer returns the function that maps a colour to the closest colour available in the output
+func (r *ImageRenderer) quantizer(profile ColourProfile, monochrome bool) func(rgbF) rgbF {
+	if monochrome {
+		threshold := r.threshold()
+		return func(c rgbF) rgbF {
+			if luminance(c) > threshold {
+				return rgbF{255, 255, 255}
+			}
+			return rgbF{}
+		}
+	}
+	var first, last Colour
+	switch profile {
+	case Colours16:
+		first, last = 0, 16
+	case Colours256:
+		// basic colours are often customised by the terminal themes, so only the colour cube and greys are used
+		first, last = 16, 256
+	default:
+		return nil
+	}
+	return func(c rgbF) rgbF {
+		pr, pg, pb := paletteRgb(nearestPaletteColour(c.r, c.g, c.b, first, last))
+		return rgbF{float64(pr), float64(pg), float64(pb)}
+	}
+}
+
+// sextantRune returns the sextant character for the bit mask of 2x3 cell. Sextant characters are numbered
+// in the order of their masks, skipping the patterns that already exist in the "Block Elements" block
+func sextantRune(mask int) rune {
+	switch mask {
+	case 0:
+		return ' '
+	case 0b010101:
+		return '▌'
+	case 0b101010:
+		return '▐'
+	case 0b111111:
+		return '█'
+	}
+	index := mask - 1
+	if mask > 0b010101 {
+		index--
+	}
+	if mask > 0b101010 {
+		index--
+	}
+	return rune(0x1FB00 + index)
+}
+
+// resample scales the image to w x h pixels averaging the source pixels that fall into each target pixel.
+// Transparent pixels are blended with black
+func resample(img image.Image, w, h int) []rgbF {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	pixels := make([]rgbF, w*h)
+	for y := 0; y < h; y++ {
+		y0 := bounds.Min.Y + y*srcH/h
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcH/h)
+		for x := 0; x < w; x++ {
+			x0 := bounds.Min.X + x*srcW/w
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcW/w)
+			var sum rgbF
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, _ := img.At(sx, sy).RGBA()
+					sum.r += float64(pr >> 8)
+					sum.g += float64(pg >> 8)
+					sum.b += float64(pb >> 8)
+				}
+			}
+			count := float64((x1 - x0) * (y1 - y0))
+			pixels[y*w+x] = rgbF{sum.r / count, sum.g / count, sum.b / count}
+		}
+	}
+	return pixels
+}
+
+// ditherFloydSteinberg reduces the colours of the pixels with quantize function diffusing the quantization error
+// to the neighbouring pixels
+func ditherFloydSteinberg(pixels []rgbF, w, h int, quantize func(rgbF) rgbF) {
+	if quantize == nil {
+		return
+	}
+	spread := func(x, y int, err rgbF, factor float64) {
+		if x < 0 || x >= w || y >= h {
+			return
+		}
+		p := &pixels[y*w+x]
+		p.r = clampF(p.r+err.r*factor, 0, 255)
+		p.g = clampF(p.g+err.g*factor, 0, 255)
+		p.b = clampF(p.b+err.b*factor, 0, 255)
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			old := pixels[y*w+x]
+			quantized := quantize(old)
+			pixels[y*w+x] = quantized
+			err := rgbF{old.r - quantized.r, old.g - quantized.g, old.b - quantized.b}
+			spread(x+1, y, err, 7.0/16)
+			spread(x-1, y+1, err, 3.0/16)
+			spread(x, y+1, err, 5.0/16)
+			spread(x+1, y+1, err, 1.0/16)
+		}
+	}
+}
+
+// fitTwoColours splits the pixels of the cell into two groups so that the average colours of the groups
+// represent the cell with the smallest error. Returns the mask of pixels in the foreground group and
+// the colours of both groups
+func fitTwoColours(cell []rgbF) (mask int, fg rgbF, bg rgbF) {
+	fullMask := 1<<len(cell) - 1
+	bestError := -1.0
+	// masks with the highest bit set are complements of the masks without it, so checking half of them is enough
+	for m := 0; m <= fullMask>>1; m++ {
+		candidate := fullMask &^ m
+		on, off := averageColours(cell, candidate)
+		var e float64
+		for i, p := range cell {
+			c := off
+			if candidate&(1<<i) != 0 {
+				c = on
+			}
+			e += colourDistance(p.r, p.g, p.b, c.r, c.g, c.b)
+		}
+		if bestError < 0 || e < bestError {
+			bestError = e
+			mask, fg, bg = candidate, on, off
+		}
+	}
+	return mask, fg, bg
+}
+
+func averageColours(cell []rgbF, mask int) (on rgbF, off rgbF) {
+	var onCount, offCount float64
+	for i, p := range cell {
+		if mask&(1<<i) != 0 {
+			on.r, on.g, on.b = on.r+p.r, on.g+p.g, on.b+p.b
+			onCount++
+		} else {
+			off.r, off.g, off.b = off.r+p.r, off.g+p.g, off.b+p.b
+			offCount++
+		}
+	}
+	if onCount > 0 {
+		on = rgbF{on.r / onCount, on.g / onCount, on.b / onCount}
+	}
+	if offCount > 0 {
+		off = rgbF{off.r / offCount, off.g / offCount, off.b / offCount}
+	}
+	return on, off
+}
+
+// luminance returns relative brightness of the colour in the range of [0..1]
+func luminance(c rgbF) float64 {
+	return (0.2126*c.r + 0.7152*c.g + 0.0722*c.b) / 255
+}
+
+func clampF(v, low, high float64) float64 {
+	return math.Max(low, math.Min(high, v))
+}
@@ -0,0 +1,109 @@
+package ansie
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func twoColourImage(w, h int, top, bottom color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			if y < h/2 {
+				img.Set(x, y, top)
+			} else {
+				img.Set(x, y, bottom)
+			}
+		}
+	}
+	return img
+}
+
+var (
+	red   = color.NRGBA{R: 255, A: 255}
+	blue  = color.NRGBA{B: 255, A: 255}
+	white = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	black = color.NRGBA{A: 255}
+)
+
+func TestImageRenderer_HalfBlocks(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	r := &ImageRenderer{}
+	s := r.Render(NewAnsi(), twoColourImage(2, 2, red, blue)).String()
+	g.Expect(s).To(Equal("\033[38;2;0;0;255m\033[48;2;255;0;0m▄▄\033[0m"))
+
+	a := NewAnsi()
+	a.SetProfile(Colours256)
+	s = r.Render(a, twoColourImage(1, 4, red, red)).String()
+	g.Expect(s).To(Equal("\033[48;5;196m \033[0m\n\033[48;5;196m \033[0m"))
+}
+
+func TestImageRenderer_Monochrome(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	r := &ImageRenderer{Mode: Quadrants}
+	g.Expect(r.Render(a, twoColourImage(4, 2, white, black)).String()).To(Equal("▀▀"))
+
+	r = &ImageRenderer{Mode: Braille}
+	g.Expect(r.Render(NewAnsi(), twoColourImage(2, 4, black, white)).String()).To(Equal("⣤"))
+
+	r = &ImageRenderer{Mode: Sextants}
+	g.Expect(r.Render(a, twoColourImage(2, 3, white, black)).String()).To(Equal("\U0001FB02"))
+}
+
+func TestImageRenderer_Size(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	img := twoColourImage(100, 50, white, black)
+	a := NewAnsi()
+	a.SetEnabled(false)
+
+	s := (&ImageRenderer{Width: 20}).Render(a, img).String()
+	lines := strings.Split(s, "\n")
+	g.Expect(lines).To(HaveLen(5))
+	g.Expect([]rune(lines[0])).To(HaveLen(20))
+
+	s = (&ImageRenderer{Mode: Braille, Height: 5}).Render(a, img).String()
+	lines = strings.Split(s, "\n")
+	g.Expect(lines).To(HaveLen(5))
+	g.Expect([]rune(lines[0])).To(HaveLen(20))
+}
+
+func TestImageRenderer_Dither(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	grey := color.NRGBA{R: 128, G: 128, B: 128, A: 255}
+	img := twoColourImage(8, 8, grey, grey)
+
+	s := (&ImageRenderer{Mode: Braille}).Render(NewAnsi(), img).String()
+	g.Expect(s).To(Equal("⣿⣿⣿⣿\n⣿⣿⣿⣿"), "Expected grey to be above threshold without dithering")
+
+	s = (&ImageRenderer{Mode: Braille, Dither: true}).Render(NewAnsi(), img).String()
+	dots := 0
+	for _, r := range s {
+		if r >= brailleBase {
+			for bits := r - brailleBase; bits > 0; bits >>= 1 {
+				dots += int(bits & 1)
+			}
+		}
+	}
+	g.Expect(dots).To(BeNumerically("~", 32, 4), "Expected about a half of the dots lit when dithering grey")
+}
+
+func TestSextantRune(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(sextantRune(0)).To(Equal(' '))
+	g.Expect(sextantRune(1)).To(Equal(rune(0x1FB00)))
+	g.Expect(sextantRune(0b010101)).To(Equal('▌'))
+	g.Expect(sextantRune(0b010110)).To(Equal(rune(0x1FB14)))
+	g.Expect(sextantRune(0b111110)).To(Equal(rune(0x1FB3B)))
+	g.Expect(sextantRune(0b111111)).To(Equal('█'))
+}
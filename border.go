@@ -0,0 +1,68 @@
+package ansie
+
+// Border is a set of characters used to draw borders of tables and boxes.
+// Each field is a string containing a single character cell.
+type Border struct {
+	Horizontal  string
+	Vertical    string
+	TopLeft     string
+	TopRight    string
+	BottomLeft  string
+	BottomRight string
+	// TopTee, BottomTee, LeftTee and RightTee join the inner lines to the outer border
+	TopTee    string
+	BottomTee string
+	LeftTee   string
+	RightTee  string
+	// Cross is the intersection of inner lines
+	Cross string
+
+	markdown bool
+}
+
+var (
+	// NoBorder doesn't draw any border, table columns are separated with spaces
+	NoBorder = Border{}
+	// AsciiBorder uses only ASCII characters: +---+
+	AsciiBorder = Border{
+		Horizontal: "-", Vertical: "|",
+		TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+",
+		TopTee: "+", BottomTee: "+", LeftTee: "+", RightTee: "+", Cross: "+",
+	}
+	// SingleBorder uses thin lines: ┌───┐
+	SingleBorder = Border{
+		Horizontal: "─", Vertical: "│",
+		TopLeft: "┌", TopRight: "┐", BottomLeft: "└", BottomRight: "┘",
+		TopTee: "┬", BottomTee: "┴", LeftTee: "├", RightTee: "┤", Cross: "┼",
+	}
+	// RoundedBorder uses thin lines with rounded corners: ╭───╮
+	RoundedBorder = Border{
+		Horizontal: "─", Vertical: "│",
+		TopLeft: "╭", TopRight: "╮", BottomLeft: "╰", BottomRight: "╯",
+		TopTee: "┬", BottomTee: "┴", LeftTee: "├", RightTee: "┤", Cross: "┼",
+	}
+	// DoubleBorder uses double lines: ╔═══╗
+	DoubleBorder = Border{
+		Horizontal: "═", Vertical: "║",
+		TopLeft: "╔", TopRight: "╗", BottomLeft: "╚", BottomRight: "╝",
+		TopTee: "╦", BottomTee: "╩", LeftTee: "╠", RightTee: "╣", Cross: "╬",
+	}
+	// HeavyBorder uses thick lines: ┏━━━┓
+	HeavyBorder = Border{
+		Horizontal: "━", Vertical: "┃",
+		TopLeft: "┏", TopRight: "┓", BottomLeft: "┗", BottomRight: "┛",
+		TopTee: "┳", BottomTee: "┻", LeftTee: "┣", RightTee: "┫", Cross: "╋",
+	}
+	// MarkdownBorder renders tables in GitHub-flavoured Markdown syntax. Boxes drawn with it use ASCII lines
+	MarkdownBorder = Border{
+		Horizontal: "-", Vertical: "|",
+		TopLeft: "|", TopRight: "|", BottomLeft: "|", BottomRight: "|",
+		TopTee: "|", BottomTee: "|", LeftTee: "|", RightTee: "|", Cross: "|",
+		markdown: true,
+	}
+)
+
+// IsNone returns true if the border doesn't have any lines
+func (b Border) IsNone() bool {
+	return b.Vertical == "" && b.Horizontal == ""
+}
@@ -0,0 +1,175 @@
+package ansie
+
+import (
+	"strings"
+)
+
+// Spacing defines the size of padding or margin on each side of the box. Top and Bottom are in lines,
+// Left and Right are in cells
+type Spacing struct {
+	Top    int
+	Right  int
+	Bottom int
+	Left   int
+}
+
+// NewSpacing creates Spacing following the CSS convention: one value applies to all sides, two values are
+// vertical and horizontal spacing, three values are top, horizontal and bottom, and four values are
+// top, right, bottom and left
+func NewSpacing(values ...int) Spacing {
+	switch len(values) {
+	case 0:
+		return Spacing{}
+	case 1:
+		return Spacing{values[0], values[0], values[0], values[0]}
+	case 2:
+		return Spacing{values[0], values[1], values[0], values[1]}
+	case 3:
+		return Spacing{values[0], values[1], values[2], values[1]}
+	default:
+		return Spacing{values[0], values[1], values[2], values[3]}
+	}
+}
+
+// nonNegative returns the spacing with negative values replaced with zero
+func (sp Spacing) nonNegative() Spacing {
+	return Spacing{max(sp.Top, 0), max(sp.Right, 0), max(sp.Bottom, 0), max(sp.Left, 0)}
+}
+
+// Box draws a border around multi-line content with optional title in the top border and footer in the bottom one.
+// The content, title and footer are measured ignoring ANSI escape sequences, so they can contain styled text.
+type Box struct {
+	// Border defines the characters of the border. The zero value is NoBorder
+	Border Border
+	// BorderStyle is the style of the border lines
+	BorderStyle Style
+	// Title is shown in the top border and Footer in the bottom border
+	Title       string
+	TitleAlign  Alignment
+	Footer      string
+	FooterAlign Alignment
+	// Align is the alignment of the content lines
+	Align Alignment
+	// Padding is the space between the border and the content. Negative values are treated as zero
+	Padding Spacing
+	// Margin is the space around the border. Negative values are treated as zero
+	Margin Spacing
+	// Width of the box including the border, but not the margin. Content lines that don't fit are wrapped.
+	// Zero fits the box to the content
+	Width int
+}
+
+// NewBox creates a box with single line border, the given title and one cell of horizontal padding
+func NewBox(title string) *Box {
+	return &Box{Border: SingleBorder, Title: title, Padding: NewSpacing(0, 1)}
+}
+
+// Render adds the box with the content to the AnsiBuffer's buffer. Every line except the last one is terminated
+// with a line break. Margins are filled with spaces, so that all the lines have the same width.
+func (b *Box) Render(a *AnsiBuffer, content string) *AnsiBuffer {
+	borderWidth := 0
+	if !b.Border.IsNone() {
+		borderWidth = 1
+	}
+	padding, margin := b.Padding.nonNegative(), b.Margin.nonNegative()
+	horizontalPadding := padding.Left + padding.Right
+	var inner int // width between the borders
+	var lines []string
+	if b.Width > 0 {
+		inner = max(b.Width-2*borderWidth, horizontalPadding+1)
+		lines = Wrap(content, inner-horizontalPadding)
+	} else {
+		lines = strings.Split(content, "\n")
+		for _, line := range lines {
+			inner = max(inner, StringWidth(line)+horizontalPadding)
+		}
+		for _, text := range []string{b.Title, b.Footer} {
+			if text != "" {
+				// one line and one space on each side of the text in the border
+				inner = max(inner, StringWidth(text)+4*borderWidth)
+			}
+		}
+	}
+	contentWidth := inner - horizontalPadding
+	outer := inner + 2*borderWidth + margin.Left + margin.Right
+	left := strings.Repeat(" ", margin.Left)
+	right := strings.Repeat(" ", margin.Right)
+
+	var rows []func()
+	blank := func() { a.A(strings.Repeat(" ", outer)) }
+	for i := 0; i < margin.Top; i++ {
+		rows = append(rows, blank)
+	}
+	if borderWidth > 0 || b.Title != "" {
+		rows = append(rows, func() {
+			a.A(left)
+			b.renderEdge(a, inner, b.Border.TopLeft, b.Border.TopRight, b.Title, b.TitleAlign)
+			a.A(right)
+		})
+	}
+	paddingLine := strings.Repeat(" ", contentWidth)
+	var body []string
+	for i := 0; i < padding.Top; i++ {
+		body = append(body, paddingLine)
+	}
+	for _, line := range lines {
+		body = append(body, Align(Truncate(line, contentWidth, ""), contentWidth, b.Align))
+	}
+	for i := 0; i < padding.Bottom; i++ {
+		body = append(body, paddingLine)
+	}
+	for _, line := range body {
+		rows = append(rows, func() {
+			a.A(left).WithStyle(b.BorderStyle, b.Border.Vertical)
+			a.A(strings.Repeat(" ", padding.Left)).A(line).A(strings.Repeat(" ", padding.Right))
+			a.WithStyle(b.BorderStyle, b.Border.Vertical).A(right)
+		})
+	}
+	if borderWidth > 0 || b.Footer != "" {
+		rows = append(rows, func() {
+			a.A(left)
+			b.renderEdge(a, inner, b.Border.BottomLeft, b.Border.BottomRight, b.Footer, b.FooterAlign)
+			a.A(right)
+		})
+	}
+	for i := 0; i < margin.Bottom; i++ {
+		rows = append(rows, blank)
+	}
+	for i, row := range rows {
+		if i > 0 {
+			a.CR()
+		}
+		row()
+	}
+	return a
+}
+
+// renderEdge draws the top or bottom border with the text embedded in it. If there is no border, only the text
+// is drawn
+func (b *Box) renderEdge(a *AnsiBuffer, width int, leftCorner, rightCorner string, text string, align Alignment) {
+	line := b.Border.Horizontal
+	gap := 1 // text in the border is separated from the corners and the line with spaces
+	if b.Border.IsNone() {
+		line = " "
+		gap = 0
+	}
+	if text != "" && width > 2*gap {
+		spaces := strings.Repeat(" ", gap)
+		text = spaces + Truncate(text, width-2*gap, "…") + spaces
+	} else {
+		text = ""
+	}
+	remaining := width - StringWidth(text)
+	before := 0
+	switch align {
+	case AlignCenter:
+		before = remaining / 2
+	case AlignRight:
+		before = max(remaining-gap, 0)
+	default:
+		before = min(remaining, gap)
+	}
+	a.WithStyle(b.BorderStyle, leftCorner+strings.Repeat(line, before))
+	a.A(text)
+	a.WithStyle(b.BorderStyle, strings.Repeat(line, remaining-before)+rightCorner)
+}
@@ -0,0 +1,87 @@
+package ansie
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNewSpacing(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(NewSpacing(1)).To(Equal(Spacing{1, 1, 1, 1}))
+	g.Expect(NewSpacing(1, 2)).To(Equal(Spacing{Top: 1, Right: 2, Bottom: 1, Left: 2}))
+	g.Expect(NewSpacing(1, 2, 3)).To(Equal(Spacing{Top: 1, Right: 2, Bottom: 3, Left: 2}))
+	g.Expect(NewSpacing(1, 2, 3, 4)).To(Equal(Spacing{Top: 1, Right: 2, Bottom: 3, Left: 4}))
+}
+
+func TestBox_Render(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	b := NewBox("Title")
+	g.Expect(b.Render(a, "hello\n日本").String()).To(Equal(strings.Join([]string{
+		"┌─ Title ─┐",
+		"│ hello   │",
+		"│ 日本    │",
+		"└─────────┘",
+	}, "\n")))
+
+	b.Border = DoubleBorder
+	b.TitleAlign = AlignRight
+	b.Footer = "ok"
+	b.FooterAlign = AlignCenter
+	b.Align = AlignCenter
+	g.Expect(b.Render(a, "hi").String()).To(Equal(strings.Join([]string{
+		"╔═ Title ═╗",
+		"║   hi    ║",
+		"╚══ ok ═══╝",
+	}, "\n")))
+}
+
+func TestBox_WidthAndSpacing(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	b := &Box{Border: AsciiBorder, Width: 8, Padding: NewSpacing(1, 1, 0, 1), Margin: NewSpacing(0, 1)}
+	g.Expect(b.Render(a, "one two three").String()).To(Equal(strings.Join([]string{
+		" +------+ ",
+		" |      | ",
+		" | one  | ",
+		" | two  | ",
+		" | thre | ",
+		" | e    | ",
+		" +------+ ",
+	}, "\n")))
+
+	b = &Box{Title: "t"}
+	g.Expect(b.Render(a, "text").String()).To(Equal("t   \ntext"), "Expected title without border")
+}
+
+func TestBox_NegativeSpacing(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	b := &Box{Border: SingleBorder, Padding: NewSpacing(-1), Margin: Spacing{Top: -2, Left: -1, Right: 1}}
+	g.Expect(b.Render(a, "x").String()).To(Equal(strings.Join([]string{
+		"┌─┐ ",
+		"│x│ ",
+		"└─┘ ",
+	}, "\n")), "Expected negative spacing to be treated as zero")
+}
+
+func TestBox_Styles(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	b := &Box{Border: RoundedBorder, BorderStyle: NewStyle().Fg(Blue)}
+	content := NewAnsi().Fg(Red).A("red").Reset().String()
+	g.Expect(b.Render(NewAnsi(), content).String()).To(Equal(strings.Join([]string{
+		"\033[34m╭─\033[0m\033[34m──╮\033[0m",
+		"\033[34m│\033[0m\033[31mred\033[0m\033[34m│\033[0m",
+		"\033[34m╰─\033[0m\033[34m──╯\033[0m",
+	}, "\n")))
+}
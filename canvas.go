@@ -0,0 +1,228 @@
+package ansie
+
+import (
+	"image"
+)
+
+const (
+	canvasCellWidth  = 2
+	canvasCellHeight = 4
+)
+
+// Canvas is a drawing surface with the resolution of 2x4 pixels per character cell. Pixels are displayed
+// using Unicode braille patterns. Pixel coordinates are 0-based, with (0, 0) in the top-left corner.
+//
+// Every character cell has its own Style, which is set from the Pen style when any pixel in the cell is drawn.
+// Drawing outside of the canvas is silently ignored.
+//
+// Use Render to get the canvas as a string or Screen.PrintBlockAt to draw it into a screen region.
+type Canvas struct {
+	cols   int
+	rows   int
+	dots   []rune
+	styles []Style
+	// marks replace the braille patterns in the cells, they are used to tell chart series apart without colours
+	marks []rune
+	// Pen is the style applied to the cells touched by the drawing operations
+	Pen Style
+}
+
+// NewCanvas creates a new empty canvas with the given size in character cells
+func NewCanvas(cols, rows int) *Canvas {
+	cols = max(cols, 0)
+	rows = max(rows, 0)
+	return &Canvas{
+		cols:   cols,
+		rows:   rows,
+		dots:   make([]rune, cols*rows),
+		styles: make([]Style, cols*rows),
+		marks:  make([]rune, cols*rows),
+	}
+}
+
+// Width returns the width of the canvas in pixels
+func (c *Canvas) Width() int {
+	return c.cols * canvasCellWidth
+}
+
+// Height returns the height of the canvas in pixels
+func (c *Canvas) Height() int {
+	return c.rows * canvasCellHeight
+}
+
+// Clear unsets all pixels and resets the styles of all cells
+func (c *Canvas) Clear() {
+	clear(c.dots)
+	clear(c.styles)
+	clear(c.marks)
+}
+
+// Set lights the pixel at (x, y)
+func (c *Canvas) Set(x, y int) {
+	if cell, dot, ok := c.locate(x, y); ok {
+		c.dots[cell] |= dot
+		c.styles[cell] = c.Pen
+	}
+}
+
+// Unset clears the pixel at (x, y)
+func (c *Canvas) Unset(x, y int) {
+	if cell, dot, ok := c.locate(x, y); ok {
+		c.dots[cell] &^= dot
+	}
+}
+
+// Toggle inverts the pixel at (x, y)
+func (c *Canvas) Toggle(x, y int) {
+	if cell, dot, ok := c.locate(x, y); ok {
+		c.dots[cell] ^= dot
+		c.styles[cell] = c.Pen
+	}
+}
+
+// IsSet checks if the pixel at (x, y) is lit
+func (c *Canvas) IsSet(x, y int) bool {
+	cell, dot, ok := c.locate(x, y)
+	return ok && c.dots[cell]&dot != 0
+}
+
+// SetCellStyle sets the style of the character cell at column col and row row, both 0-based
+func (c *Canvas) SetCellStyle(col, row int, style Style) {
+	if col >= 0 && row >= 0 && col < c.cols && row < c.rows {
+		c.styles[row*c.cols+col] = style
+	}
+}
+
+// Line draws a line from (x0, y0) to (x1, y1) using Bresenham's algorithm
+func (c *Canvas) Line(x0, y0, x1, y1 int) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		c.Set(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// Rect draws an outline of the rectangle with corners at (x0, y0) and (x1, y1)
+func (c *Canvas) Rect(x0, y0, x1, y1 int) {
+	c.Polygon(image.Pt(x0, y0), image.Pt(x1, y0), image.Pt(x1, y1), image.Pt(x0, y1))
+}
+
+// FillRect lights all pixels of the rectangle with corners at (x0, y0) and (x1, y1)
+func (c *Canvas) FillRect(x0, y0, x1, y1 int) {
+	for y := min(y0, y1); y <= max(y0, y1); y++ {
+		c.Line(x0, y, x1, y)
+	}
+}
+
+// Circle draws an outline of the circle with the centre at (cx, cy) using midpoint circle algorithm
+func (c *Canvas) Circle(cx, cy, radius int) {
+	x, y := radius, 0
+	e := 1 - radius
+	for x >= y {
+		for _, p := range [][2]int{{x, y}, {y, x}, {-y, x}, {-x, y}, {-x, -y}, {-y, -x}, {y, -x}, {x, -y}} {
+			c.Set(cx+p[0], cy+p[1])
+		}
+		y++
+		if e < 0 {
+			e += 2*y + 1
+		} else {
+			x--
+			e += 2*(y-x) + 1
+		}
+	}
+}
+
+// Polygon draws a closed polygon connecting the points with lines
+func (c *Canvas) Polygon(points ...image.Point) {
+	for i, p := range points {
+		next := points[(i+1)%len(points)]
+		c.Line(p.X, p.Y, next.X, next.Y)
+	}
+}
+
+// Render adds the canvas to the AnsiBuffer's buffer, one line per row of character cells.
+// Empty cells are rendered as spaces. Every row except the last one is terminated with a line break.
+func (c *Canvas) Render(a *AnsiBuffer) *AnsiBuffer {
+	for row := 0; row < c.rows; row++ {
+		c.renderRow(a, row)
+		if row < c.rows-1 {
+			a.CR()
+		}
+	}
+	return a
+}
+
+// renderRow adds a single row of character cells to the AnsiBuffer's buffer
+func (c *Canvas) renderRow(a *AnsiBuffer, row int) {
+	current := Style{}
+	for col := 0; col < c.cols; col++ {
+		i := row*c.cols + col
+		if style := c.styles[i]; style != current {
+			if !current.IsDefault() {
+				a.Reset()
+			}
+			a.Style(style)
+			current = style
+		}
+		if c.marks[i] != 0 {
+			a.A(string(c.marks[i]))
+		} else if c.dots[i] == 0 {
+			a.A(" ")
+		} else {
+			a.A(string(brailleBase + c.dots[i]))
+		}
+	}
+	if !current.IsDefault() {
+		a.Reset()
+	}
+}
+
+// String renders the canvas as a plain text without colours
+func (c *Canvas) String() string {
+	a := NewAnsi()
+	a.SetEnabled(false)
+	return c.Render(a).String()
+}
+
+// mark shows the character instead of the braille pattern in the cell containing the pixel (x, y)
+func (c *Canvas) mark(x, y int, r rune) {
+	if cell, _, ok := c.locate(x, y); ok {
+		c.marks[cell] = r
+		c.styles[cell] = c.Pen
+	}
+}
+
+func (c *Canvas) locate(x, y int) (cell int, dot rune, ok bool) {
+	if x < 0 || y < 0 || x >= c.Width() || y >= c.Height() {
+		return 0, 0, false
+	}
+	cell = (y/canvasCellHeight)*c.cols + x/canvasCellWidth
+	return cell, brailleDots[y%canvasCellHeight][x%canvasCellWidth], true
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,76 @@
+package ansie
+
+import (
+	"image"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCanvas_SetUnsetToggle(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	c := NewCanvas(2, 1)
+	g.Expect(c.Width()).To(Equal(4))
+	g.Expect(c.Height()).To(Equal(4))
+
+	c.Set(0, 0)
+	c.Set(1, 3)
+	c.Set(10, 10)
+	g.Expect(c.IsSet(0, 0)).To(BeTrue())
+	g.Expect(c.IsSet(1, 0)).To(BeFalse())
+	g.Expect(c.String()).To(Equal("⢁ "))
+
+	c.Unset(0, 0)
+	c.Toggle(2, 0)
+	c.Toggle(1, 3)
+	g.Expect(c.String()).To(Equal(" ⠁"))
+
+	c.Clear()
+	g.Expect(c.String()).To(Equal("  "))
+}
+
+func TestCanvas_Line(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	c := NewCanvas(2, 1)
+	c.Line(0, 0, 3, 3)
+	g.Expect(c.String()).To(Equal("⠑⢄"))
+
+	c.Clear()
+	c.Line(3, 1, 0, 1)
+	g.Expect(c.String()).To(Equal("⠒⠒"))
+}
+
+func TestCanvas_Shapes(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	c := NewCanvas(2, 1)
+	c.Rect(0, 0, 3, 3)
+	g.Expect(c.String()).To(Equal("⣏⣹"))
+
+	c.Clear()
+	c.FillRect(0, 0, 3, 3)
+	g.Expect(c.String()).To(Equal("⣿⣿"))
+
+	c = NewCanvas(3, 2)
+	c.Circle(2, 3, 2)
+	g.Expect(c.String()).To(Equal("⡔⠒⡄\n⠑⠒⠁"))
+
+	c = NewCanvas(2, 1)
+	c.Polygon(image.Pt(0, 0), image.Pt(3, 0), image.Pt(0, 3))
+	g.Expect(c.String()).To(Equal("⡯⠋"))
+}
+
+func TestCanvas_Render(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	c := NewCanvas(3, 2)
+	c.Pen = NewStyle().Fg(Red)
+	c.Set(0, 0)
+	c.Pen = NewStyle()
+	c.Set(2, 0)
+	c.SetCellStyle(2, 1, NewStyle().Bg(Blue))
+	s := c.Render(NewAnsi()).String()
+	g.Expect(s).To(Equal("\033[31m⠁\033[0m⠁ \n  \033[44m \033[0m"))
+}
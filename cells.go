@@ -0,0 +1,273 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Cell is a single character cell of the Screen's cell buffer
+type Cell struct {
+	// Text is the character displayed in the cell, together with the zero-width characters that follow it,
+	// like combining marks. Empty text is displayed as a space
+	Text string
+	// Width is the number of cells the character occupies: 1 or 2 for wide characters. The cell following
+	// the wide character is a continuation cell with zero width. If Width is zero, SetCell calculates it from Text
+	Width int
+	// Style is the style of the cell
+	Style Style
+	// Link is the URL of the hyperlink the cell belongs to, if any
+	Link string
+}
+
+var blankCell = Cell{Text: " ", Width: 1}
+
+// cellGrid is a rectangular array of cells, stored row by row
+type cellGrid struct {
+	width  int
+	height int
+	cells  []Cell
+}
+
+func newCellGrid(width, height int) *cellGrid {
+	g := &cellGrid{width: max(width, 0), height: max(height, 0)}
+	g.cells = make([]Cell, g.width*g.height)
+	for i := range g.cells {
+		g.cells[i] = blankCell
+	}
+	return g
+}
+
+// resized returns a grid of the new size with the cells of this grid copied into it
+func (g *cellGrid) resized(width, height int) *cellGrid {
+	r := newCellGrid(width, height)
+	for row := 0; row < min(g.height, r.height); row++ {
+		for col := 0; col < min(g.width, r.width); col++ {
+			r.put(col, row, g.cells[row*g.width+col])
+		}
+	}
+	return r
+}
+
+// put sets the cell at zero-based position. Wide characters that are partially overwritten are replaced
+// with spaces
+func (g *cellGrid) put(col, row int, c Cell) {
+	if col < 0 || row < 0 || col >= g.width || row >= g.height {
+		return
+	}
+	if c.Width == 0 {
+		// continuation cells are only written together with the wide characters
+		return
+	}
+	if c.Width > 1 && col == g.width-1 {
+		c = Cell{Text: " ", Width: 1, Style: c.Style, Link: c.Link}
+	}
+	g.breakWide(col, row)
+	g.cells[row*g.width+col] = c
+	if c.Width > 1 {
+		g.breakWide(col+1, row)
+		g.cells[row*g.width+col+1] = Cell{Width: 0, Style: c.Style, Link: c.Link}
+	}
+}
+
+// breakWide replaces the wide character occupying the cell with spaces
+func (g *cellGrid) breakWide(col, row int) {
+	i := row*g.width + col
+	switch {
+	case g.cells[i].Width == 0 && col > 0:
+		g.cells[i-1] = Cell{Text: " ", Width: 1, Style: g.cells[i-1].Style, Link: g.cells[i-1].Link}
+	case g.cells[i].Width > 1 && col+1 < g.width:
+		g.cells[i+1] = Cell{Text: " ", Width: 1, Style: g.cells[i].Style, Link: g.cells[i].Link}
+	}
+}
+
+// SetCell sets the cell at (x, y) in the cell buffer. Changes are displayed by Show.
+// Coordinates are 1-based, where (1, 1) is the top-left corner. Cells outside the screen are ignored.
+// A wide character that doesn't fit in the last column is replaced with a space.
+func (s *Screen) SetCell(x, y int, c Cell) {
+	s.cellsMu.Lock()
+	defer s.cellsMu.Unlock()
+	s.ensureCells()
+	s.back.put(x-1, y-1, normaliseCell(c))
+}
+
+// GetCell returns the cell at (x, y) in the cell buffer. Coordinates are 1-based. Cells outside the screen
+// are returned as blanks
+func (s *Screen) GetCell(x, y int) Cell {
+	s.cellsMu.Lock()
+	defer s.cellsMu.Unlock()
+	s.ensureCells()
+	if x < 1 || y < 1 || x > s.back.width || y > s.back.height {
+		return blankCell
+	}
+	return s.back.cells[(y-1)*s.back.width+x-1]
+}
+
+// SetString writes the text into the cell buffer starting at (x, y) using the style and returns the number of
+// cells used. The text is clipped at the right edge of the screen. Escape sequences and control characters,
+// including line breaks, are removed. Coordinates are 1-based.
+func (s *Screen) SetString(x, y int, text string, style Style) int {
+	s.cellsMu.Lock()
+	defer s.cellsMu.Unlock()
+	s.ensureCells()
+	col := x - 1
+	var cell Cell
+	flush := func() {
+		if cell.Text != "" {
+			s.back.put(col, y-1, cell)
+			col += cell.Width
+		}
+	}
+	for _, r := range StripAnsi(text) {
+		w := RuneWidth(r)
+		switch {
+		case r < 0x20 || (r >= 0x7f && r < 0xa0):
+			continue
+		case w == 0:
+			if cell.Text != "" {
+				cell.Text += string(r)
+			}
+			continue
+		}
+		flush()
+		if col+w > s.back.width {
+			cell = Cell{}
+			break
+		}
+		cell = Cell{Text: string(r), Width: w, Style: style}
+	}
+	flush()
+	return max(col-(x-1), 0)
+}
+
+// Fill sets all the cells of the rectangle with the top-left corner at (x, y), width w and height h
+// to the cell. Coordinates are 1-based.
+func (s *Screen) Fill(x, y, w, h int, c Cell) {
+	s.cellsMu.Lock()
+	defer s.cellsMu.Unlock()
+	s.ensureCells()
+	c = normaliseCell(c)
+	for row := y - 1; row < y-1+h; row++ {
+		for col := x - 1; col+c.Width <= x-1+w; col += c.Width {
+			s.back.put(col, row, c)
+		}
+	}
+}
+
+// Sync makes the next Show clear the terminal and draw the whole cell buffer. Use it when the terminal
+// content was changed without using the cell buffer.
+func (s *Screen) Sync() {
+	s.cellsMu.Lock()
+	defer s.cellsMu.Unlock()
+	s.redraw = true
+}
+
+// Show updates the terminal to display the cell buffer. Only the cells that changed since the previous call
+// are written, using the minimal cursor movements and style changes. The update is written at once, wrapped
+// in synchronised output mode, so that terminals that support it don't show partial frames.
+func (s *Screen) Show() {
+	s.cellsMu.Lock()
+	defer s.cellsMu.Unlock()
+	s.ensureCells()
+	var sb strings.Builder
+	if s.redraw {
+		sb.WriteString(esc + "2J")
+		s.front = newCellGrid(s.back.width, s.back.height)
+		s.redraw = false
+	}
+	back, front := s.back, s.front
+	var style Style
+	link := ""
+	cx, cy := -1, -1
+	for row := 0; row < back.height; row++ {
+		for col := 0; col < back.width; col++ {
+			i := row*back.width + col
+			c := back.cells[i]
+			if c.Width == 0 || (c == front.cells[i] && (c.Width == 1 || back.cells[i+1] == front.cells[i+1])) {
+				continue
+			}
+			switch {
+			case cy == row && cx == col:
+			case cy == row && cx >= 0 && cx < col:
+				sb.WriteString(cursorForward(col - cx))
+			default:
+				sb.WriteString(fmt.Sprintf("%s%d;%dH", esc, row+1, col+1))
+			}
+			if c.Style != style {
+				o := sgrOptimiser{state: style}
+				sb.WriteString(o.diff(c.Style))
+				style = c.Style
+			}
+			if c.Link != link {
+				sb.WriteString(hyperlink(c.Link))
+				link = c.Link
+			}
+			sb.WriteString(c.Text)
+			copy(front.cells[i:i+c.Width], back.cells[i:i+c.Width])
+			cx, cy = col+c.Width, row
+			if cx >= back.width {
+				// the cursor stays in the last column until the next character is written
+				cx = -1
+			}
+		}
+	}
+	if sb.Len() == 0 {
+		return
+	}
+	if !style.IsDefault() {
+		sb.WriteString(esc + "0m")
+	}
+	if link != "" {
+		sb.WriteString(hyperlink(""))
+	}
+	s.write(esc + "?2026h" + sb.String() + esc + "?2026l")
+}
+
+// ensureCells allocates the cell buffers of the screen size. If the size of the screen changed, the content of
+// the cell buffer is preserved and the next Show redraws the whole screen
+func (s *Screen) ensureCells() {
+	width, height := s.Size()
+	if s.back == nil {
+		s.back = newCellGrid(width, height)
+		s.front = newCellGrid(width, height)
+		return
+	}
+	if s.back.width != width || s.back.height != height {
+		s.back = s.back.resized(width, height)
+		s.redraw = true
+	}
+}
+
+// resetCells marks the terminal as blank after it was cleared without using the cell buffer
+func (s *Screen) resetCells() {
+	s.cellsMu.Lock()
+	defer s.cellsMu.Unlock()
+	if s.front != nil {
+		s.front = newCellGrid(s.front.width, s.front.height)
+	}
+}
+
+func normaliseCell(c Cell) Cell {
+	// zero-width characters can't be displayed in a cell on their own
+	if StringWidth(c.Text) == 0 {
+		c.Text = " "
+	}
+	if c.Width <= 0 {
+		c.Width = StringWidth(c.Text)
+	}
+	c.Width = min(max(c.Width, 1), 2)
+	return c
+}
+
+func cursorForward(n int) string {
+	if n == 1 {
+		return esc + "C"
+	}
+	return fmt.Sprintf("%s%dC", esc, n)
+}
+
+// hyperlink returns OSC 8 sequence starting the hyperlink to url or ending the hyperlink if url is empty
+func hyperlink(url string) string {
+	return "\033]8;;" + url + "\033\\"
+}
@@ -0,0 +1,70 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func newTestScreen(g *WithT, width, height int) (*Screen, *MockTerminal) {
+	m := NewMockTerminal(width, height)
+	s, err := NewScreenFromTerminal(m)
+	g.Expect(err).To(BeNil(), "Expected no error when creating a new screen")
+	m.ResetBuffer()
+	return s, m
+}
+
+func TestScreen_SetString(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, _ := newTestScreen(g, 10, 3)
+	defer s.Close()
+
+	red := NewStyle().Fg(Red)
+	g.Expect(s.SetString(2, 1, "aé日x\n", red)).To(Equal(5))
+	g.Expect(s.GetCell(2, 1)).To(Equal(Cell{Text: "a", Width: 1, Style: red}))
+	g.Expect(s.GetCell(3, 1)).To(Equal(Cell{Text: "é", Width: 1, Style: red}), "Expected combining mark to join the character")
+	g.Expect(s.GetCell(4, 1).Width).To(Equal(2))
+	g.Expect(s.GetCell(5, 1).Width).To(Equal(0), "Expected continuation cell after wide character")
+	g.Expect(s.GetCell(6, 1).Text).To(Equal("x"))
+
+	g.Expect(s.SetString(8, 2, "abcdef", red)).To(Equal(3), "Expected text to be clipped")
+	g.Expect(s.SetString(10, 3, "日", red)).To(Equal(0), "Expected wide character not to fit")
+
+	s.SetCell(5, 1, Cell{Text: "z"})
+	g.Expect(s.GetCell(4, 1)).To(Equal(Cell{Text: " ", Width: 1, Style: red}), "Expected overwritten wide character to be replaced")
+}
+
+func TestScreen_Show(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 10, 3)
+	defer s.Close()
+
+	s.Show()
+	g.Expect(m.Buffer.String()).To(BeEmpty(), "Expected no output without changes")
+
+	bold := NewStyle().Attr(Bold)
+	s.SetString(1, 1, "ab", bold)
+	s.SetString(6, 1, "c", NewStyle())
+	s.SetCell(1, 2, Cell{Text: "d", Link: "http://example.com"})
+	s.Show()
+	g.Expect(m.Buffer.String()).To(Equal("\033[?2026h\033[1;1H\033[1mab\033[3C\033[0mc" +
+		"\033[2;1H\033]8;;http://example.com\033\\d\033]8;;\033\\\033[?2026l"))
+
+	m.ResetBuffer()
+	s.SetString(2, 1, "x", bold)
+	s.SetString(1, 1, "a", bold)
+	s.Show()
+	g.Expect(m.Buffer.String()).To(Equal("\033[?2026h\033[1;2H\033[1mx\033[0m\033[?2026l"), "Expected only changed cells to be written")
+
+	m.ResetBuffer()
+	s.Fill(1, 3, 10, 1, Cell{Text: "-"})
+	s.Show()
+	g.Expect(m.Buffer.String()).To(Equal("\033[?2026h\033[3;1H----------\033[?2026l"))
+
+	m.ResetBuffer()
+	s.Sync()
+	s.Show()
+	g.Expect(m.Buffer.String()).To(HavePrefix("\033[?2026h\033[2J\033[1;1H\033[1max\033[3C"), "Expected full redraw")
+}
@@ -0,0 +1,136 @@
+package ansie
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const defaultValueFormat = "%g"
+
+// block characters of increasing height, from one eighth to the full cell
+var verticalEighths = []rune("▁▂▃▄▅▆▇█")
+
+// block characters of increasing width, from one eighth to the full cell
+var horizontalEighths = []rune("▏▎▍▌▋▊▉█")
+
+// fill characters used to tell the series apart when colours are not available
+var monochromePatterns = []rune("█▓▒░")
+
+// Sparkline renders a series of values as a single line of block characters of different height, like ▁▂▃▅▇
+type Sparkline struct {
+	// Width is the maximum number of values shown. If there are more values, only the most recent ones are shown.
+	// Zero shows all values
+	Width int
+	// Min and Max define the range of the values. If both are zero, the range is calculated from the values
+	Min float64
+	Max float64
+	// Style of the sparkline
+	Style Style
+}
+
+// Render adds the sparkline for values to the AnsiBuffer's buffer. NaN and infinite values are rendered as gaps.
+func (s *Sparkline) Render(a *AnsiBuffer, values []float64) *AnsiBuffer {
+	if s.Width > 0 && len(values) > s.Width {
+		values = values[len(values)-s.Width:]
+	}
+	low, high := s.Min, s.Max
+	if low == 0 && high == 0 {
+		low, high = valueRange(values)
+	}
+	var sb strings.Builder
+	for _, v := range values {
+		switch {
+		case !isFinite(v):
+			sb.WriteRune(' ')
+		case high <= low:
+			sb.WriteRune(verticalEighths[len(verticalEighths)/2-1])
+		default:
+			level := int(math.Round((clampF(v, low, high) - low) / (high - low) * float64(len(verticalEighths)-1)))
+			sb.WriteRune(verticalEighths[level])
+		}
+	}
+	return a.WithStyle(s.Style, sb.String())
+}
+
+// chartCell is a single character cell of the chart
+type chartCell struct {
+	r     rune
+	style Style
+}
+
+func blankCells(n int) []chartCell {
+	cells := make([]chartCell, max(n, 0))
+	for i := range cells {
+		cells[i].r = ' '
+	}
+	return cells
+}
+
+// putText writes the text into the cells starting at position pos. Text that doesn't fit is dropped
+func putText(cells []chartCell, pos int, text string, style Style) {
+	for _, r := range StripAnsi(text) {
+		if pos < 0 || pos >= len(cells) {
+			return
+		}
+		cells[pos] = chartCell{r: r, style: style}
+		pos += max(RuneWidth(r), 1)
+	}
+}
+
+// renderCells adds the cells to the AnsiBuffer's buffer grouping consecutive cells of the same style
+func renderCells(a *AnsiBuffer, cells []chartCell) {
+	var run strings.Builder
+	var style Style
+	for i, c := range cells {
+		if i > 0 && c.style != style {
+			a.WithStyle(style, run.String())
+			run.Reset()
+		}
+		style = c.style
+		run.WriteRune(c.r)
+	}
+	if run.Len() > 0 {
+		a.WithStyle(style, run.String())
+	}
+}
+
+// renderLegend adds a line with the series names marked with their styles
+func renderLegend(a *AnsiBuffer, names []string, styles []Style, marker func(i int) rune) {
+	for i, name := range names {
+		if i > 0 {
+			a.A("  ")
+		}
+		a.WithStyle(styles[i], string(marker(i))).A(" ").A(name)
+	}
+}
+
+func isMonochrome(a *AnsiBuffer) bool {
+	return !a.IsEnabled() || a.Profile() == NoColour
+}
+
+func formatValue(format string, v float64) string {
+	if format == "" {
+		format = defaultValueFormat
+	}
+	return fmt.Sprintf(format, v)
+}
+
+// valueRange returns the smallest and the largest of the values ignoring NaNs and infinities
+func valueRange(values []float64) (low, high float64) {
+	low, high = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if isFinite(v) {
+			low = math.Min(low, v)
+			high = math.Max(high, v)
+		}
+	}
+	if math.IsInf(low, 1) {
+		return 0, 0
+	}
+	return low, high
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
@@ -0,0 +1,33 @@
+package ansie
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSparkline(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	s := &Sparkline{}
+	g.Expect(s.Render(NewAnsi(), []float64{0, 1, 2, 3, 4, 5, 6, 7}).String()).To(Equal("▁▂▃▄▅▆▇█"))
+	g.Expect(s.Render(NewAnsi(), []float64{1, math.NaN(), 3}).String()).To(Equal("▁ █"))
+	g.Expect(s.Render(NewAnsi(), []float64{2, 2}).String()).To(Equal("▄▄"))
+	g.Expect(s.Render(NewAnsi(), []float64{1, math.Inf(1), 3, math.Inf(-1)}).String()).To(Equal("▁ █ "),
+		"Expected infinite values to be gaps")
+
+	s = &Sparkline{Width: 3, Min: 0, Max: 10, Style: NewStyle().Fg(Green)}
+	g.Expect(s.Render(NewAnsi(), []float64{10, 0, 5, 10}).String()).To(Equal("\033[32m▁▅█\033[0m"))
+}
+
+func TestSparkline_Monochrome(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetProfile(NoColour)
+	s := &Sparkline{Style: NewStyle().Fg(Green)}
+	g.Expect(s.Render(a, []float64{0, 7}).String()).To(Equal("▁█\033[0m"))
+	a.SetEnabled(false)
+	g.Expect(s.Render(a, []float64{0, 7}).String()).To(Equal("▁█"))
+}
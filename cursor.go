@@ -0,0 +1,126 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CursorShape is the shape of the text cursor
+type CursorShape int
+
+const (
+	// CursorDefault is the shape configured in the terminal
+	CursorDefault CursorShape = iota
+	CursorBlock
+	CursorUnderline
+	CursorBar
+)
+
+const cursorQueryTimeout = 500 * time.Millisecond
+
+// SetCursorStyle changes the shape of the cursor and makes it blinking or steady. CursorDefault restores the
+// terminal's default cursor, the blink flag is ignored then. If the shape was changed, Close resets it to
+// the terminal's default, which is not necessarily the shape the cursor had before, as most terminals can't report it.
+func (s *Screen) SetCursorStyle(shape CursorShape, blink bool) {
+	code := 0
+	if shape != CursorDefault {
+		code = int(shape) * 2
+		if blink {
+			code--
+		}
+	}
+	s.writeEsc(fmt.Sprintf("%d q", code))
+	s.cursorStyled = shape != CursorDefault
+}
+
+// SetCursorColourRgb changes the colour of the cursor. If the colour was changed, Close resets it to the terminal's
+// default, like ResetCursorColour.
+func (s *Screen) SetCursorColourRgb(r, g, b uint) {
+	s.write(fmt.Sprintf("\033]12;#%02x%02x%02x\033\\", clip(r, 255), clip(g, 255), clip(b, 255)))
+	s.cursorColoured = true
+}
+
+// ResetCursorColour restores the default colour of the cursor
+func (s *Screen) ResetCursorColour() {
+	s.write("\033]112\033\\")
+	s.cursorColoured = false
+}
+
+// SaveCursor saves the position of the cursor and the text attributes, they are restored with RestoreCursor
+func (s *Screen) SaveCursor() {
+	s.write("\0337")
+}
+
+// RestoreCursor moves the cursor to the position saved with SaveCursor and restores the text attributes
+func (s *Screen) RestoreCursor() {
+	s.write("\0338")
+}
+
+// CursorUp moves the cursor up by count rows, it stops at the top edge of the screen
+func (s *Screen) CursorUp(count int) {
+	s.moveCursor('A', count)
+}
+
+// CursorDown moves the cursor down by count rows, it stops at the bottom edge of the screen
+func (s *Screen) CursorDown(count int) {
+	s.moveCursor('B', count)
+}
+
+// CursorRight moves the cursor right by count columns, it stops at the right edge of the screen
+func (s *Screen) CursorRight(count int) {
+	s.moveCursor('C', count)
+}
+
+// CursorLeft moves the cursor left by count columns, it stops at the left edge of the screen
+func (s *Screen) CursorLeft(count int) {
+	s.moveCursor('D', count)
+}
+
+func (s *Screen) moveCursor(command byte, count int) {
+	// terminals move the cursor by one cell when count is zero
+	if count < 1 {
+		return
+	}
+	s.writeEsc(fmt.Sprintf("%d%c", count, command))
+}
+
+// CursorPosition asks the terminal for the position of the cursor and returns its 1-based coordinates.
+// Keys pressed while waiting for the reply are kept and can be read later. If the terminal doesn't reply,
+// it returns ScreenError with ErrTimeout cause.
+func (s *Screen) CursorPosition() (x, y int, err error) {
+	reply, err := s.input.query(func() { s.writeEsc("6n") }, matchCursorPosition, cursorQueryTimeout)
+	if err != nil {
+		return 0, 0, err
+	}
+	row, col, _ := bytes.Cut(reply[len(esc):len(reply)-1], []byte(";"))
+	y, _ = strconv.Atoi(string(row))
+	x, _ = strconv.Atoi(string(col))
+	return x, y, nil
+}
+
+// matchCursorPosition matches "CSI row;col R" cursor position report
+func matchCursorPosition(buf []byte) (int, int) {
+	for start := 0; start < len(buf); start++ {
+		i := bytes.Index(buf[start:], []byte(esc))
+		if i < 0 {
+			return -1, -1
+		}
+		start += i
+		end := start + len(esc)
+		separators := 0
+		for end < len(buf) && (buf[end] >= '0' && buf[end] <= '9' || buf[end] == ';') {
+			if buf[end] == ';' {
+				separators++
+			}
+			end++
+		}
+		if end < len(buf) && buf[end] == 'R' && separators == 1 {
+			return start, end + 1
+		}
+	}
+	return -1, -1
+}
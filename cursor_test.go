@@ -0,0 +1,121 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestScreen_CursorPosition(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+	defer s.Close()
+
+	m.SendInput("a\033[5;12Rb")
+	x, y, err := s.CursorPosition()
+	g.Expect(err).To(BeNil())
+	g.Expect(x).To(Equal(12))
+	g.Expect(y).To(Equal(5))
+	g.Expect(m.Buffer.String()).To(Equal("\033[6n"))
+
+	ev, _ := s.ReadEvent(time.Second)
+	g.Expect(ev).To(Equal(KeyEvent{Rune: 'a'}), "Expected keys before the reply to be preserved")
+	ev, _ = s.ReadEvent(time.Second)
+	g.Expect(ev).To(Equal(KeyEvent{Rune: 'b'}), "Expected keys after the reply to be preserved")
+	g.Expect(s.input.pending).To(BeEmpty())
+}
+
+func TestScreen_CursorPositionWithEvents(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := s.Events(ctx)
+	go func() {
+		// the reply arrives after the query, while the events are being read
+		for !strings.Contains(m.Output(), "\033[6n") {
+			time.Sleep(time.Millisecond)
+		}
+		m.SendInput("a\033[1;5Rb")
+	}()
+	x, y, err := s.CursorPosition()
+	g.Expect(err).To(BeNil())
+	g.Expect(x).To(Equal(5))
+	g.Expect(y).To(Equal(1))
+
+	g.Eventually(events).Should(Receive(Equal(KeyEvent{Rune: 'a'})))
+	g.Eventually(events).Should(Receive(Equal(KeyEvent{Rune: 'b'})), "Expected the reply not to be reported as Ctrl+F3")
+}
+
+func TestScreen_CursorPositionTimeout(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+	defer s.Close()
+
+	m.SendInput("\033[1;5R")
+	_, err := s.ReadEvent(time.Second)
+	g.Expect(err).To(BeNil())
+	_, _, err = s.CursorPosition()
+	g.Expect(errors.Is(err, ErrTimeout)).To(BeTrue())
+}
+
+func TestScreen_SetCursorStyle(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+
+	s.SetCursorStyle(CursorBlock, true)
+	s.SetCursorStyle(CursorUnderline, false)
+	s.SetCursorStyle(CursorBar, true)
+	g.Expect(m.Buffer.String()).To(Equal("\033[1 q\033[4 q\033[5 q"))
+
+	m.ResetBuffer()
+	s.Close()
+	g.Expect(m.Buffer.String()).To(HavePrefix("\033[0 q"), "Expected default shape to be restored on close")
+}
+
+func TestScreen_SetCursorColourRgb(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+
+	s.SetCursorColourRgb(255, 128, 300)
+	g.Expect(m.Buffer.String()).To(Equal("\033]12;#ff80ff\033\\"))
+
+	m.ResetBuffer()
+	s.Close()
+	g.Expect(m.Buffer.String()).To(HavePrefix("\033]112\033\\"), "Expected colour to be reset on close")
+}
+
+func TestScreen_CloseKeepsUnchangedCursor(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+
+	s.SetCursorColourRgb(0, 0, 0)
+	s.ResetCursorColour()
+	m.ResetBuffer()
+	s.Close()
+	g.Expect(m.Buffer.String()).NotTo(ContainSubstring(" q"))
+	g.Expect(m.Buffer.String()).NotTo(ContainSubstring("\033]112"))
+}
+
+func TestScreen_CursorMovement(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+	defer s.Close()
+
+	s.SaveCursor()
+	s.CursorUp(2)
+	s.CursorDown(3)
+	s.CursorRight(4)
+	s.CursorLeft(5)
+	s.CursorLeft(0)
+	s.RestoreCursor()
+	g.Expect(m.Buffer.String()).To(Equal("\0337\033[2A\033[3B\033[4C\033[5D\0338"))
+}
@@ -0,0 +1,372 @@
+package ansie
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const defaultDiffContext = 3
+
+// DiffLineKind is the kind of the line in the diff hunk
+type DiffLineKind int
+
+const (
+	DiffContext DiffLineKind = iota
+	DiffRemoved
+	DiffAdded
+)
+
+// DiffLine is a single line of the diff hunk. OldNumber and NewNumber are 1-based line numbers in the old and
+// new text, zero if the line is not present in the text
+type DiffLine struct {
+	Kind      DiffLineKind
+	Text      string
+	OldNumber int
+	NewNumber int
+}
+
+// DiffHunk is a group of changed lines with the surrounding context
+type DiffHunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	// Section is the text following the range information in the hunk header, usually a function name
+	Section string
+	Lines   []DiffLine
+}
+
+// Header returns the unified diff header of the hunk, like "@@ -1,3 +1,4 @@"
+func (h *DiffHunk) Header() string {
+	header := fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+	if h.Section != "" {
+		header += " " + h.Section
+	}
+	return header
+}
+
+// Diff is a list of changes between two texts
+type Diff struct {
+	OldName string
+	NewName string
+	Hunks   []DiffHunk
+}
+
+// NewDiff compares old and new texts line by line and creates the Diff with the given number of context lines
+// around the changes. Negative context uses the default of 3 lines.
+func NewDiff(oldName, oldText, newName, newText string, context int) *Diff {
+	if context < 0 {
+		context = defaultDiffContext
+	}
+	oldLines, newLines := splitLines(oldText), splitLines(newText)
+	var lines []DiffLine
+	for _, op := range diffSequences(oldLines, newLines) {
+		switch op.kind {
+		case diffEqual:
+			lines = append(lines, DiffLine{DiffContext, oldLines[op.old], op.old + 1, op.new + 1})
+		case diffDelete:
+			lines = append(lines, DiffLine{DiffRemoved, oldLines[op.old], op.old + 1, 0})
+		case diffInsert:
+			lines = append(lines, DiffLine{DiffAdded, newLines[op.new], 0, op.new + 1})
+		}
+	}
+	return &Diff{OldName: oldName, NewName: newName, Hunks: groupHunks(lines, context)}
+}
+
+// ParseUnifiedDiff parses the output of `diff -u` or `git diff`. The text can contain changes of several files.
+// Lines that are neither file headers nor hunks, like `git` extended headers, are ignored.
+func ParseUnifiedDiff(text string) ([]*Diff, error) {
+	var diffs []*Diff
+	var current *Diff
+	var hunk *DiffHunk
+	oldRemaining, newRemaining := 0, 0
+	oldNumber, newNumber := 0, 0
+	for i, line := range splitLines(text) {
+		if hunk != nil && (oldRemaining > 0 || newRemaining > 0) {
+			if line == "" {
+				line = " " // some tools strip the trailing space of empty context lines
+			}
+			switch line[0] {
+			case ' ':
+				oldNumber++
+				newNumber++
+				oldRemaining--
+				newRemaining--
+				hunk.Lines = append(hunk.Lines, DiffLine{DiffContext, line[1:], oldNumber, newNumber})
+				continue
+			case '-':
+				oldNumber++
+				oldRemaining--
+				hunk.Lines = append(hunk.Lines, DiffLine{DiffRemoved, line[1:], oldNumber, 0})
+				continue
+			case '+':
+				newNumber++
+				newRemaining--
+				hunk.Lines = append(hunk.Lines, DiffLine{DiffAdded, line[1:], 0, newNumber})
+				continue
+			case '\\': // "\ No newline at end of file"
+				continue
+			default:
+				return nil, fmt.Errorf("line %d: unexpected line in hunk: %q", i+1, line)
+			}
+		}
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			current = &Diff{OldName: diffFileName(line[4:])}
+			diffs = append(diffs, current)
+			hunk = nil
+		case strings.HasPrefix(line, "+++ ") && current != nil && current.NewName == "" && len(current.Hunks) == 0:
+			current.NewName = diffFileName(line[4:])
+		case strings.HasPrefix(line, "@@ "):
+			if current == nil {
+				current = &Diff{}
+				diffs = append(diffs, current)
+			}
+			h, err := parseHunkHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			current.Hunks = append(current.Hunks, h)
+			hunk = &current.Hunks[len(current.Hunks)-1]
+			oldRemaining, newRemaining = h.OldLines, h.NewLines
+			oldNumber, newNumber = h.OldStart-1, h.NewStart-1
+			if h.OldLines == 0 {
+				oldNumber = h.OldStart
+			}
+			if h.NewLines == 0 {
+				newNumber = h.NewStart
+			}
+		}
+	}
+	if hunk != nil && (oldRemaining > 0 || newRemaining > 0) {
+		return nil, fmt.Errorf("unexpected end of hunk %s", hunk.Header())
+	}
+	return diffs, nil
+}
+
+func parseHunkHeader(line string) (DiffHunk, error) {
+	var h DiffHunk
+	end := strings.Index(line[3:], " @@")
+	if end < 0 {
+		return h, fmt.Errorf("invalid hunk header: %q", line)
+	}
+	ranges := strings.Fields(line[3 : 3+end])
+	if len(ranges) != 2 || !strings.HasPrefix(ranges[0], "-") || !strings.HasPrefix(ranges[1], "+") {
+		return h, fmt.Errorf("invalid hunk header: %q", line)
+	}
+	var err1, err2 error
+	h.OldStart, h.OldLines, err1 = parseHunkRange(ranges[0][1:])
+	h.NewStart, h.NewLines, err2 = parseHunkRange(ranges[1][1:])
+	if err1 != nil || err2 != nil {
+		return h, fmt.Errorf("invalid hunk header: %q", line)
+	}
+	h.Section = strings.TrimSpace(line[3+end+3:])
+	return h, nil
+}
+
+func parseHunkRange(r string) (start, count int, err error) {
+	count = 1
+	if s, c, found := strings.Cut(r, ","); found {
+		if count, err = strconv.Atoi(c); err != nil {
+			return 0, 0, err
+		}
+		r = s
+	}
+	start, err = strconv.Atoi(r)
+	return start, count, err
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// diffFileName removes the timestamp that diff adds after the file name
+func diffFileName(name string) string {
+	name, _, _ = strings.Cut(name, "\t")
+	return name
+}
+
+// groupHunks splits the lines into hunks, leaving up to context unchanged lines around the changes
+func groupHunks(lines []DiffLine, context int) []DiffHunk {
+	var hunks []DiffHunk
+	for i := 0; i < len(lines); {
+		if lines[i].Kind == DiffContext {
+			i++
+			continue
+		}
+		start := max(i-context, 0)
+		end := i
+		// extend the hunk while the next change is close enough for the contexts to overlap
+		for j := i; j < len(lines); j++ {
+			if lines[j].Kind != DiffContext {
+				end = j
+			} else if j-end > 2*context {
+				break
+			}
+		}
+		end = min(end+context+1, len(lines))
+		hunks = append(hunks, newHunk(lines[:start], lines[start:end]))
+		i = end
+	}
+	return hunks
+}
+
+// newHunk creates a hunk from the lines, the lines before the hunk are used to find the position of empty ranges
+func newHunk(before []DiffLine, lines []DiffLine) DiffHunk {
+	h := DiffHunk{Lines: lines}
+	for _, line := range before {
+		h.OldStart = max(h.OldStart, line.OldNumber)
+		h.NewStart = max(h.NewStart, line.NewNumber)
+	}
+	// an empty range starts at the line before the hunk, otherwise the range starts at the first line of the hunk
+	if slices.ContainsFunc(lines, func(l DiffLine) bool { return l.OldNumber > 0 }) {
+		h.OldStart++
+	}
+	if slices.ContainsFunc(lines, func(l DiffLine) bool { return l.NewNumber > 0 }) {
+		h.NewStart++
+	}
+	for _, line := range lines {
+		if line.OldNumber > 0 {
+			h.OldLines++
+		}
+		if line.NewNumber > 0 {
+			h.NewLines++
+		}
+	}
+	return h
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// splitWords splits the line into words, runs of spaces and single punctuation characters for the intra-line diff
+func splitWords(line string) []string {
+	var words []string
+	start := 0
+	class := func(r rune) int {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		default:
+			return 3
+		}
+	}
+	prev := 0
+	for i, r := range line {
+		c := class(r)
+		if i > start && (c != prev || c == 3) {
+			words = append(words, line[start:i])
+			start = i
+		}
+		prev = c
+	}
+	if start < len(line) {
+		words = append(words, line[start:])
+	}
+	return words
+}
+
+type diffOpKind int
+
+const (
+	diffEqual diffOpKind = iota
+	diffDelete
+	diffInsert
+)
+
+// diffOp is a single edit operation, old and new are the indices of the element in the old and new sequence
+type diffOp struct {
+	kind diffOpKind
+	old  int
+	new  int
+}
+
+// diffSequences finds the shortest edit script transforming a into b using Myers' algorithm.
+// Deletions are placed before insertions in each group of changes.
+func diffSequences(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds the furthest reaching x for diagonals -d..d at the start of round d
+	var trace [][]int
+	found := false
+	for d := 0; d <= n+m && !found; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		prevX, prevY := 0, 0
+		if d > 0 {
+			at := func(k int) int { return trace[d][k+d] }
+			k := x - y
+			prevK := k - 1
+			if k == -d || (k != d && at(k-1) < at(k+1)) {
+				prevK = k + 1
+			}
+			prevX = at(prevK)
+			prevY = prevX - prevK
+		}
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, diffOp{diffEqual, x, y})
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{diffInsert, x, prevY})
+			} else {
+				ops = append(ops, diffOp{diffDelete, prevX, y})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	// move deletions in front of insertions in every group of changes
+	for i := 0; i < len(ops); {
+		if ops[i].kind == diffEqual {
+			i++
+			continue
+		}
+		j := i
+		for j < len(ops) && ops[j].kind != diffEqual {
+			j++
+		}
+		slices.SortStableFunc(ops[i:j], func(a, b diffOp) int { return int(a.kind) - int(b.kind) })
+		i = j
+	}
+	return ops
+}
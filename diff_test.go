@@ -0,0 +1,138 @@
+package ansie
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	diffOld = "a\nb\nc\nd\ne\nf\ng\nh\nthe quick brown fox\ni\n"
+	diffNew = "a\nB\nc\nd\ne\nf\ng\nh\nthe slow brown fox\nnew\ni\n"
+)
+
+func TestDiffSequences(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ops := diffSequences(strings.Split("abcabba", ""), strings.Split("cbabac", ""))
+	oldCount, newCount, equal := 0, 0, 0
+	for _, op := range ops {
+		switch op.kind {
+		case diffEqual:
+			equal++
+		case diffDelete:
+			oldCount++
+		case diffInsert:
+			newCount++
+		}
+	}
+	g.Expect(equal).To(Equal(4), "Expected the longest common subsequence")
+	g.Expect(oldCount).To(Equal(3))
+	g.Expect(newCount).To(Equal(2))
+
+	g.Expect(diffSequences(nil, []string{"a"})).To(Equal([]diffOp{{diffInsert, 0, 0}}))
+	g.Expect(diffSequences([]string{"a", "b"}, []string{"c", "d"})).To(Equal([]diffOp{
+		{diffDelete, 0, 0}, {diffDelete, 1, 0}, {diffInsert, 2, 0}, {diffInsert, 2, 1},
+	}), "Expected deletions before insertions")
+}
+
+func TestNewDiff(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	d := NewDiff("old", diffOld, "new", diffNew, 1)
+	g.Expect(d.Hunks).To(HaveLen(2))
+	g.Expect(d.Hunks[0].Header()).To(Equal("@@ -1,3 +1,3 @@"))
+	g.Expect(d.Hunks[1].Header()).To(Equal("@@ -8,3 +8,4 @@"))
+	g.Expect(d.Hunks[1].Lines).To(Equal([]DiffLine{
+		{DiffContext, "h", 8, 8},
+		{DiffRemoved, "the quick brown fox", 9, 0},
+		{DiffAdded, "the slow brown fox", 0, 9},
+		{DiffAdded, "new", 0, 10},
+		{DiffContext, "i", 10, 11},
+	}))
+
+	g.Expect(NewDiff("", diffOld, "", diffNew, -1).Hunks).To(HaveLen(1), "Expected hunks to merge with default context")
+	g.Expect(NewDiff("", "a\n", "", "a\nb\n", 0).Hunks[0].Header()).To(Equal("@@ -1,0 +2 @@"))
+}
+
+func TestParseUnifiedDiff(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	text := strings.Join([]string{
+		"diff --git a/x b/x",
+		"index 1234..5678 100644",
+		"--- a/x\t2024-01-01",
+		"+++ b/x",
+		"@@ -1,3 +1,3 @@ func main()",
+		" a",
+		"--- b",
+		"+b",
+		"",
+		"\\ No newline at end of file",
+		"--- a/y",
+		"+++ b/y",
+		"@@ -0,0 +1 @@",
+		"+new",
+	}, "\n")
+	diffs, err := ParseUnifiedDiff(text)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(diffs).To(HaveLen(2))
+	g.Expect(diffs[0].OldName).To(Equal("a/x"))
+	g.Expect(diffs[0].NewName).To(Equal("b/x"))
+	g.Expect(diffs[0].Hunks[0].Section).To(Equal("func main()"))
+	g.Expect(diffs[0].Hunks[0].Lines).To(Equal([]DiffLine{
+		{DiffContext, "a", 1, 1},
+		{DiffRemoved, "-- b", 2, 0},
+		{DiffAdded, "b", 0, 2},
+		{DiffContext, "", 3, 3},
+	}))
+	g.Expect(diffs[1].Hunks[0].Lines).To(Equal([]DiffLine{{DiffAdded, "new", 0, 1}}))
+
+	_, err = ParseUnifiedDiff("@@ -1,2 +1,2 @@\n a\n")
+	g.Expect(err).To(HaveOccurred())
+	_, err = ParseUnifiedDiff("@@ -x +1 @@\n")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestDiffRenderer_Unified(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	r := &DiffRenderer{LineNumbers: true}
+	g.Expect(r.Render(a, NewDiff("old", diffOld, "new", diffNew, 0)).String()).To(Equal(strings.Join([]string{
+		"--- old",
+		"+++ new",
+		"@@ -2 +2 @@",
+		" 2    │-b",
+		"    2 │+B",
+		"@@ -9 +9,2 @@",
+		" 9    │-the quick brown fox",
+		"    9 │+the slow brown fox",
+		"   10 │+new",
+	}, "\n")))
+
+	r = &DiffRenderer{WordDiff: true}
+	d := NewDiff("", "the quick brown fox\n", "", "the slow brown fox\n", 0)
+	g.Expect(r.Render(NewAnsi(), d).String()).To(Equal(strings.Join([]string{
+		"\033[36m@@ -1 +1 @@\033[0m",
+		"\033[31m-the \033[0m\033[97;48;5;88mquick\033[0m\033[31m brown fox\033[0m",
+		"\033[32m+the \033[0m\033[97;48;5;22mslow\033[0m\033[32m brown fox\033[0m",
+	}, "\n")))
+}
+
+func TestDiffRenderer_SideBySide(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	r := &DiffRenderer{SideBySide: true, Width: 27}
+	d := NewDiff("", "x\tlong line here\nsame\n", "", "y\nsame\nadded\n", 1)
+	g.Expect(r.Render(a, d).String()).To(Equal(strings.Join([]string{
+		"@@ -1,2 +1,3 @@",
+		"-x    long … │ +y",
+		" same        │  same",
+		"             │ +added",
+	}, "\n")))
+}
@@ -0,0 +1,265 @@
+package ansie
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultDiffWidth = 120
+	diffTabWidth     = 4
+	diffSeparator    = " │ "
+)
+
+// DiffStyles define the styles of the diff elements
+type DiffStyles struct {
+	// Header is the style of the file names
+	Header Style
+	// Hunk is the style of the hunk headers
+	Hunk Style
+	// Removed and Added are the styles of the changed lines
+	Removed Style
+	Added   Style
+	// RemovedWord and AddedWord are the styles of the changed words within the changed lines
+	RemovedWord Style
+	AddedWord   Style
+	// LineNumber is the style of the line numbers and the separator between the sides of side-by-side diff
+	LineNumber Style
+}
+
+// DefaultDiffStyles are the styles used by DiffRenderer if no styles are set
+var DefaultDiffStyles = DiffStyles{
+	Header:      NewStyle().Attr(Bold),
+	Hunk:        NewStyle().Fg(Cyan),
+	Removed:     NewStyle().Fg(Red),
+	Added:       NewStyle().Fg(Green),
+	RemovedWord: NewStyle().FgHi(White).Bg(DarkRed),
+	AddedWord:   NewStyle().FgHi(White).Bg(DarkGreen),
+	LineNumber:  NewStyle().Attr(Faint),
+}
+
+// DiffRenderer renders Diff as coloured unified diff or as two columns side by side.
+// When the AnsiBuffer has colours disabled, the output is a plain unified diff.
+type DiffRenderer struct {
+	// Styles default to DefaultDiffStyles
+	Styles *DiffStyles
+	// LineNumbers adds the numbers of the lines in the old and new text
+	LineNumbers bool
+	// WordDiff highlights changed words in the pairs of removed and added lines
+	WordDiff bool
+	// SideBySide shows the old text on the left and the new text on the right
+	SideBySide bool
+	// Width of the side-by-side diff in cells, lines that don't fit are truncated. Zero uses the width of the terminal
+	// connected to the standard output, or 120 if the standard output is not a terminal. See also FitScreen
+	Width int
+}
+
+// diffSpan is a fragment of a diff line, changed fragments are highlighted in the word diff
+type diffSpan struct {
+	text    string
+	changed bool
+}
+
+// Render adds the diff to the AnsiBuffer's buffer. Every line except the last one is terminated with a line break.
+func (r *DiffRenderer) Render(a *AnsiBuffer, d *Diff) *AnsiBuffer {
+	styles := r.Styles
+	if styles == nil {
+		styles = &DefaultDiffStyles
+	}
+	numberWidth := 0
+	if r.LineNumbers {
+		for _, h := range d.Hunks {
+			numberWidth = max(numberWidth, len(strconv.Itoa(h.OldStart+h.OldLines)), len(strconv.Itoa(h.NewStart+h.NewLines)))
+		}
+	}
+	lines := 0
+	newLine := func() {
+		if lines > 0 {
+			a.CR()
+		}
+		lines++
+	}
+	if d.OldName != "" || d.NewName != "" {
+		newLine()
+		a.WithStyle(styles.Header, "--- "+d.OldName)
+		newLine()
+		a.WithStyle(styles.Header, "+++ "+d.NewName)
+	}
+	width := r.Width
+	if width <= 0 {
+		width = defaultDiffWidth
+		if w, ok := terminalWidth(os.Stdout); ok {
+			width = w
+		}
+	}
+	for _, h := range d.Hunks {
+		newLine()
+		if r.SideBySide {
+			a.WithStyle(styles.Hunk, Truncate(h.Header(), width, "…"))
+		} else {
+			a.WithStyle(styles.Hunk, h.Header())
+		}
+		spans := r.lineSpans(h.Lines)
+		if r.SideBySide {
+			r.renderSideBySide(a, styles, h.Lines, spans, numberWidth, width, newLine)
+			continue
+		}
+		for i, line := range h.Lines {
+			newLine()
+			if r.LineNumbers {
+				a.WithStyle(styles.LineNumber,
+					lineNumber(line.OldNumber, numberWidth)+" "+lineNumber(line.NewNumber, numberWidth)+" │")
+			}
+			renderDiffLine(a, styles, line.Kind, spans[i])
+		}
+	}
+	return a
+}
+
+func (r *DiffRenderer) renderSideBySide(a *AnsiBuffer, styles *DiffStyles, lines []DiffLine, spans [][]diffSpan,
+	numberWidth int, width int, newLine func()) {
+	column := max((width-StringWidth(diffSeparator))/2, 1)
+	cell := func(index int, number int) string {
+		if index < 0 {
+			return strings.Repeat(" ", column)
+		}
+		c := a.scratch()
+		if r.LineNumbers {
+			c.WithStyle(styles.LineNumber, lineNumber(number, numberWidth)+" ")
+		}
+		expanded := make([]diffSpan, len(spans[index]))
+		for i, span := range spans[index] {
+			expanded[i] = diffSpan{strings.ReplaceAll(span.text, "\t", strings.Repeat(" ", diffTabWidth)), span.changed}
+		}
+		renderDiffLine(c, styles, lines[index].Kind, expanded)
+		return Align(Truncate(c.String(), column, "…"), column, AlignLeft)
+	}
+	row := func(left, right int) {
+		newLine()
+		oldNumber, newNumber := 0, 0
+		if left >= 0 {
+			oldNumber = lines[left].OldNumber
+		}
+		if right >= 0 {
+			newNumber = lines[right].NewNumber
+		}
+		a.A(cell(left, oldNumber)).WithStyle(styles.LineNumber, diffSeparator)
+		a.A(strings.TrimRight(cell(right, newNumber), " "))
+	}
+	for i := 0; i < len(lines); {
+		if lines[i].Kind == DiffContext {
+			row(i, i)
+			i++
+			continue
+		}
+		removed, added := changeGroup(lines, i)
+		for k := 0; k < max(len(removed), len(added)); k++ {
+			left, right := -1, -1
+			if k < len(removed) {
+				left = removed[k]
+			}
+			if k < len(added) {
+				right = added[k]
+			}
+			row(left, right)
+		}
+		i += len(removed) + len(added)
+	}
+}
+
+// renderDiffLine adds the marker and the line spans to the buffer
+func renderDiffLine(a *AnsiBuffer, styles *DiffStyles, kind DiffLineKind, spans []diffSpan) {
+	style, changed, marker := Style{}, Style{}, " "
+	switch kind {
+	case DiffRemoved:
+		style, changed, marker = styles.Removed, styles.RemovedWord, "-"
+	case DiffAdded:
+		style, changed, marker = styles.Added, styles.AddedWord, "+"
+	}
+	text := marker
+	for _, span := range spans {
+		if !span.changed {
+			text += span.text
+			continue
+		}
+		a.WithStyle(style, text).WithStyle(changed, span.text)
+		text = ""
+	}
+	if text != "" {
+		a.WithStyle(style, text)
+	}
+}
+
+// lineSpans splits the lines into spans. With word diff enabled, the removed and added lines in each group of
+// changes are paired in order and the words that differ are marked as changed
+func (r *DiffRenderer) lineSpans(lines []DiffLine) [][]diffSpan {
+	spans := make([][]diffSpan, len(lines))
+	for i, line := range lines {
+		spans[i] = []diffSpan{{text: line.Text}}
+	}
+	if !r.WordDiff {
+		return spans
+	}
+	for i := 0; i < len(lines); {
+		if lines[i].Kind == DiffContext {
+			i++
+			continue
+		}
+		removed, added := changeGroup(lines, i)
+		for k := 0; k < min(len(removed), len(added)); k++ {
+			if old, new, ok := wordDiff(lines[removed[k]].Text, lines[added[k]].Text); ok {
+				spans[removed[k]], spans[added[k]] = old, new
+			}
+		}
+		i += len(removed) + len(added)
+	}
+	return spans
+}
+
+// changeGroup returns the indices of the removed lines and the following added lines starting at index start
+func changeGroup(lines []DiffLine, start int) (removed, added []int) {
+	i := start
+	for ; i < len(lines) && lines[i].Kind == DiffRemoved; i++ {
+		removed = append(removed, i)
+	}
+	for ; i < len(lines) && lines[i].Kind == DiffAdded; i++ {
+		added = append(added, i)
+	}
+	return removed, added
+}
+
+// wordDiff compares the lines word by word. It returns false if the lines don't have any common words,
+// as highlighting the whole line doesn't add any information
+func wordDiff(oldLine, newLine string) (oldSpans, newSpans []diffSpan, ok bool) {
+	oldWords, newWords := splitWords(oldLine), splitWords(newLine)
+	add := func(spans []diffSpan, text string, changed bool) []diffSpan {
+		if n := len(spans); n > 0 && spans[n-1].changed == changed {
+			spans[n-1].text += text
+			return spans
+		}
+		return append(spans, diffSpan{text, changed})
+	}
+	for _, op := range diffSequences(oldWords, newWords) {
+		switch op.kind {
+		case diffEqual:
+			if strings.TrimSpace(oldWords[op.old]) != "" {
+				ok = true
+			}
+			oldSpans = add(oldSpans, oldWords[op.old], false)
+			newSpans = add(newSpans, newWords[op.new], false)
+		case diffDelete:
+			oldSpans = add(oldSpans, oldWords[op.old], true)
+		case diffInsert:
+			newSpans = add(newSpans, newWords[op.new], true)
+		}
+	}
+	return oldSpans, newSpans, ok
+}
+
+func lineNumber(n int, width int) string {
+	if n <= 0 {
+		return strings.Repeat(" ", width)
+	}
+	return Align(strconv.Itoa(n), width, AlignRight)
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package ansie
+
+// FitScreen sets the width of the side-by-side diff to the width of the screen
+func (r *DiffRenderer) FitScreen(s *Screen) *DiffRenderer {
+	r.Width, _ = s.Size()
+	return r
+}
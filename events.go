@@ -0,0 +1,225 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"context"
+	"os"
+	"sync"
+)
+
+const eventQueueSize = 64
+
+// ResizeEvent is reported when the terminal is resized
+type ResizeEvent struct {
+	Width  int
+	Height int
+}
+
+func (ResizeEvent) isEvent() {}
+
+// SignalEvent is reported when the process receives SIGINT or SIGTERM while the events are read with Events.
+// The screen is not closed automatically in this case
+type SignalEvent struct {
+	Signal os.Signal
+}
+
+func (SignalEvent) isEvent() {}
+
+// UserEvent is an event posted by the application with Screen.Post
+type UserEvent struct {
+	Data any
+}
+
+func (UserEvent) isEvent() {}
+
+// ErrorEvent is reported when the input can't be read from the terminal. No more input events are reported after it
+type ErrorEvent struct {
+	Err error
+}
+
+func (ErrorEvent) isEvent() {}
+
+// eventLoop merges the events from the terminal input, signals and the application into a single channel
+type eventLoop struct {
+	queue chan Event
+	// resized and signals carry the events of the signal handler. They hold a single pending event and never block,
+	// so a burst of resizes is reported once
+	resized chan struct{}
+	signals chan os.Signal
+	out     chan Event
+	// done is closed when the loop stops
+	done chan struct{}
+	// sendMu prevents sending to the queue after it is drained
+	sendMu sync.RWMutex
+	// readerDone is closed when the input is no longer read by the loop
+	readerDone chan struct{}
+}
+
+// Events starts reading the input and returns the channel of all the events: keys, mouse, paste and focus events,
+// resizes, signals and the events posted with Post. While the events are read, SIGINT and SIGTERM are reported
+// as SignalEvent instead of closing the screen.
+// The channel is closed when ctx is done or the screen is closed. Calling Events again while the channel is open
+// returns the same channel. Don't call ReadEvent while the events are read with Events.
+// The events that were not received from the channel when it is closed are kept and returned by ReadEvent
+// or by the channel of the next Events call.
+func (s *Screen) Events(ctx context.Context) <-chan Event {
+	s.eventsMu.Lock()
+	defer s.eventsMu.Unlock()
+	if s.events != nil {
+		select {
+		case <-s.events.done:
+			// only one goroutine may read the input, wait until the stopped loop releases it
+			<-s.events.readerDone
+		default:
+			return s.events.out
+		}
+	}
+	loop := &eventLoop{
+		queue:      make(chan Event, eventQueueSize),
+		resized:    make(chan struct{}, 1),
+		signals:    make(chan os.Signal, 1),
+		out:        make(chan Event),
+		done:       make(chan struct{}),
+		readerDone: make(chan struct{}),
+	}
+	s.events = loop
+	go s.dispatchEvents(ctx, loop)
+	go s.readInputEvents(loop)
+	return loop.out
+}
+
+// Post sends the event to the channel returned by Events. It returns false if the events are not being read.
+// Post waits while the channel's buffer is full
+func (s *Screen) Post(ev Event) bool {
+	loop := s.eventLoop()
+	if loop == nil {
+		return false
+	}
+	return loop.send(ev)
+}
+
+// postResize reports the resize to the event loop without blocking. The size is read when the event is delivered
+func (s *Screen) postResize() {
+	if loop := s.eventLoop(); loop != nil && loop.running() {
+		select {
+		case loop.resized <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// postSignal reports the signal to the event loop without blocking. It returns false if the events are not being read
+func (s *Screen) postSignal(sig os.Signal) bool {
+	loop := s.eventLoop()
+	if loop == nil || !loop.running() {
+		return false
+	}
+	select {
+	case loop.signals <- sig:
+	default:
+		// the same or another termination signal is already pending
+	}
+	return true
+}
+
+// eventLoop returns the running or the last stopped event loop
+func (s *Screen) eventLoop() *eventLoop {
+	s.eventsMu.Lock()
+	defer s.eventsMu.Unlock()
+	return s.events
+}
+
+func (s *Screen) dispatchEvents(ctx context.Context, loop *eventLoop) {
+	// undelivered is the event taken from the queue when ctx is done
+	var undelivered Event
+	defer func() { s.stopEvents(loop, undelivered) }()
+	for {
+		var ev Event
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.closing:
+			return
+		case ev = <-loop.queue:
+		case <-loop.resized:
+			width, height := s.Size()
+			ev = ResizeEvent{Width: width, Height: height}
+		case sig := <-loop.signals:
+			ev = SignalEvent{Signal: sig}
+		}
+		select {
+		case loop.out <- ev:
+		case <-ctx.Done():
+			undelivered = ev
+			return
+		case <-s.closing:
+			return
+		}
+	}
+}
+
+// stopEvents closes the channel and returns the events that were not delivered to the input reader
+func (s *Screen) stopEvents(loop *eventLoop, undelivered Event) {
+	close(loop.done)
+	close(loop.out)
+	<-loop.readerDone
+	loop.sendMu.Lock()
+	defer loop.sendMu.Unlock()
+	var pending []Event
+	if undelivered != nil {
+		pending = append(pending, undelivered)
+	}
+	for {
+		select {
+		case ev := <-loop.queue:
+			pending = append(pending, ev)
+		default:
+			s.input.unread(pending...)
+			return
+		}
+	}
+}
+
+func (s *Screen) readInputEvents(loop *eventLoop) {
+	defer close(loop.readerDone)
+	for {
+		if !loop.running() {
+			return
+		}
+		ev, err := s.input.readEvent(inputPollInterval)
+		if err != nil {
+			loop.send(ErrorEvent{Err: err})
+			return
+		}
+		if ev != nil && !loop.send(ev) {
+			// the loop stopped, keep the event for the next reader
+			s.input.unread(ev)
+			return
+		}
+	}
+}
+
+func (loop *eventLoop) running() bool {
+	select {
+	case <-loop.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// send puts the event into the queue, it returns false if the loop is stopped
+func (loop *eventLoop) send(ev Event) bool {
+	loop.sendMu.RLock()
+	defer loop.sendMu.RUnlock()
+	if !loop.running() {
+		return false
+	}
+	select {
+	case loop.queue <- ev:
+		return true
+	case <-loop.done:
+		return false
+	}
+}
@@ -0,0 +1,100 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/sys/unix"
+)
+
+func TestScreen_Events(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+	defer s.Close()
+
+	g.Expect(s.Post(UserEvent{Data: 1})).To(BeFalse(), "Expected post to fail when events are not read")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := s.Events(ctx)
+	g.Expect(s.Events(ctx)).To(Equal(events), "Expected the same channel")
+
+	m.SendInput("q")
+	g.Eventually(events).Should(Receive(Equal(KeyEvent{Rune: 'q'})))
+
+	g.Expect(s.Post(UserEvent{Data: "refresh"})).To(BeTrue())
+	g.Eventually(events).Should(Receive(Equal(UserEvent{Data: "refresh"})))
+
+	m.SetSize(100, 30, s.signals)
+	g.Eventually(events).Should(Receive(Equal(ResizeEvent{Width: 100, Height: 30})))
+
+	s.signals <- unix.SIGINT
+	g.Eventually(events).Should(Receive(Equal(SignalEvent{Signal: unix.SIGINT})))
+	g.Expect(s.closed.Load()).To(BeFalse(), "Expected screen to stay open")
+
+	cancel()
+	g.Eventually(events).Should(BeClosed())
+	g.Expect(s.Post(UserEvent{})).To(BeFalse())
+}
+
+func TestScreen_EventsClosedWithScreen(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, _ := newTestScreen(g, 80, 24)
+
+	events := s.Events(context.Background())
+	s.Close()
+	g.Eventually(events, time.Second).Should(BeClosed())
+}
+
+func TestScreen_EventsKeptAfterCancel(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := s.Events(ctx)
+	for i := range 3 {
+		g.Expect(s.Post(UserEvent{Data: i})).To(BeTrue())
+	}
+	m.SendInput("a")
+	g.Eventually(func() int { return len(s.eventLoop().queue) }).Should(Equal(3), "Expected the key to be queued")
+	cancel()
+	g.Eventually(events).Should(BeClosed())
+	g.Expect(s.Post(UserEvent{})).To(BeFalse())
+
+	for _, expected := range []Event{UserEvent{Data: 0}, UserEvent{Data: 1}, UserEvent{Data: 2}, KeyEvent{Rune: 'a'}} {
+		ev, err := s.ReadEvent(time.Second)
+		g.Expect(err).To(BeNil())
+		g.Expect(ev).To(Equal(expected), "Expected undelivered events to be kept in order")
+	}
+
+	m.SendInput("b")
+	events = s.Events(context.Background())
+	g.Eventually(events).Should(Receive(Equal(KeyEvent{Rune: 'b'})), "Expected new loop to read the input")
+}
+
+func TestScreen_InternalEventsDontBlock(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+	defer s.Close()
+
+	events := s.Events(context.Background())
+	m.SetSize(100, 30, nil)
+	_, _ = s.resize()
+	for range 3 {
+		s.postResize()
+		g.Expect(s.postSignal(unix.SIGTERM)).To(BeTrue())
+	}
+	received := map[Event]int{}
+	for range 2 {
+		var ev Event
+		g.Eventually(events).Should(Receive(&ev))
+		received[ev]++
+	}
+	g.Consistently(events, 100*time.Millisecond).ShouldNot(Receive(), "Expected pending events to be coalesced")
+	g.Expect(received).To(Equal(map[Event]int{ResizeEvent{Width: 100, Height: 30}: 1, SignalEvent{Signal: unix.SIGTERM}: 1}))
+}
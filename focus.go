@@ -0,0 +1,25 @@
+//go:build !windows
+
+package ansie
+
+// FocusEvent is reported when the terminal window gains or loses focus
+type FocusEvent struct {
+	Focused bool
+}
+
+func (FocusEvent) isEvent() {}
+
+// EnableFocusEvents makes the terminal report when its window gains or loses focus. The changes are received
+// with ReadEvent as FocusEvent. Focus reporting is disabled when the screen is closed.
+func (s *Screen) EnableFocusEvents() {
+	s.writeEsc("?1004h")
+	s.focusEvents = true
+}
+
+// DisableFocusEvents disables reporting of the focus changes
+func (s *Screen) DisableFocusEvents() {
+	if s.focusEvents {
+		s.writeEsc("?1004l")
+		s.focusEvents = false
+	}
+}
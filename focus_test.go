@@ -0,0 +1,31 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestScreen_FocusEvents(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+
+	s.EnableFocusEvents()
+	g.Expect(m.Buffer.String()).To(Equal("\033[?1004h"))
+
+	m.SendInput("\033[O\033[Ix")
+	ev, err := s.ReadEvent(time.Second)
+	g.Expect(err).To(BeNil())
+	g.Expect(ev).To(Equal(FocusEvent{Focused: false}))
+	ev, _ = s.ReadEvent(time.Second)
+	g.Expect(ev).To(Equal(FocusEvent{Focused: true}))
+	ev, _ = s.ReadEvent(time.Second)
+	g.Expect(ev).To(Equal(KeyEvent{Rune: 'x'}))
+
+	m.ResetBuffer()
+	s.Close()
+	g.Expect(m.Buffer.String()).To(HavePrefix("\033[?1004l"), "Expected focus reporting to be disabled on close")
+}
@@ -7,11 +7,11 @@ toolchain go1.23.1
 require (
 	github.com/onsi/gomega v1.38.0
 	golang.org/x/sys v0.34.0
+	golang.org/x/text v0.27.0
 )
 
 require (
 	github.com/google/go-cmp v0.7.0 // indirect
 	golang.org/x/net v0.42.0 // indirect
-	golang.org/x/text v0.27.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,231 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"bytes"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	inputBufferSize      = 4096
+	defaultEscapeTimeout = 50 * time.Millisecond
+	// inputPollInterval is the longest time the input is read without releasing the lock, so that the replies
+	// to the queries can be read while waiting for the events
+	inputPollInterval = 50 * time.Millisecond
+)
+
+// inputReader buffers the bytes read from the terminal, so that replies to the queries sent to the
+// terminal can be picked out of the input stream without losing keystrokes that arrive in between.
+type inputReader struct {
+	mu sync.Mutex
+	// term is nil if the terminal can't read the input
+	term    TerminalReader
+	pending []byte
+	// lastInput is the time the last bytes were received
+	lastInput time.Time
+	// escapeTimeout is the time to wait for the rest of the escape sequence before ESC is reported as a key
+	escapeTimeout time.Duration
+	// pasting is true between the start and the end of the bracketed paste, the text is collected in paste
+	pasting        bool
+	paste          []byte
+	pasteTruncated bool
+	maxPasteSize   int
+	// expected are the matchers of the replies to the queries in progress. The replies are not decoded as events
+	// even if they look like keys, for example cursor position report "CSI 1;5R" is the same as Ctrl+F3
+	expected []*replyMatcher
+	// unreadEvents are returned before decoding more input, they were read, but not delivered by the event loop
+	unreadEvents []Event
+}
+
+// replyMatcher looks for a complete terminal reply in buf and returns its boundaries.
+// If there is no complete reply in the buffer it must return -1, -1.
+type replyMatcher func(buf []byte) (start, end int)
+
+func newInputReader(term Terminal) *inputReader {
+	reader, _ := term.(TerminalReader)
+	return &inputReader{term: reader, escapeTimeout: defaultEscapeTimeout, maxPasteSize: DefaultMaxPasteSize}
+}
+
+// setEscapeTimeout changes the time to wait for the rest of the escape sequence
+func (r *inputReader) setEscapeTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.escapeTimeout = timeout
+}
+
+// readEvent waits at most timeout for an input event, negative timeout waits indefinitely.
+// It returns nil event if the timeout expires. Replies to the queries are left in the input stream.
+func (r *inputReader) readEvent(timeout time.Duration) (Event, error) {
+	deadline := time.Now().Add(timeout)
+	buf := make([]byte, inputBufferSize)
+	for {
+		ev, err := r.readEventOnce(buf, deadline, timeout < 0)
+		if ev != nil || err != nil || (timeout >= 0 && !time.Now().Before(deadline)) {
+			return ev, err
+		}
+	}
+}
+
+func (r *inputReader) readEventOnce(buf []byte, deadline time.Time, wait bool) (Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.unreadEvents) > 0 {
+		ev := r.unreadEvents[0]
+		r.unreadEvents = r.unreadEvents[1:]
+		return ev, nil
+	}
+	incomplete := time.Since(r.lastInput) < r.escapeTimeout
+	if ev := r.decodePending(!incomplete); ev != nil {
+		return ev, nil
+	}
+	timeout := inputPollInterval
+	if !wait {
+		timeout = min(timeout, time.Until(deadline))
+	}
+	if len(r.pending) > 0 && incomplete {
+		timeout = min(timeout, r.escapeTimeout-time.Since(r.lastInput))
+	}
+	n, err := r.read(buf, max(timeout, 0))
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		r.pending = append(r.pending, buf[:n]...)
+		r.lastInput = time.Now()
+	}
+	return r.decodePending(false), nil
+}
+
+// unread returns the events to the reader, they are read again before the rest of the input
+func (r *inputReader) unread(events ...Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unreadEvents = append(slices.Clone(events), r.unreadEvents...)
+}
+
+// decodePending removes the first event from the pending input and returns it. Replies to the pending queries are
+// skipped, but kept in the input, other replies are dropped. If final is true, incomplete sequences are decoded as separate keys.
+func (r *inputReader) decodePending(final bool) Event {
+	for i := 0; i < len(r.pending); {
+		if r.pasting {
+			ev, ok := r.readPaste(i)
+			if !ok {
+				return nil
+			}
+			return ev
+		}
+		if n := r.expectedReplyLength(r.pending[i:]); n > 0 {
+			i += n
+			continue
+		}
+		ev, n := decodeEvent(r.pending[i:], final)
+		if n == 0 {
+			return nil
+		}
+		if _, ok := ev.(terminalReply); ok {
+			// late reply to a query which timed out
+			r.pending = append(r.pending[:i], r.pending[i+n:]...)
+			continue
+		}
+		r.pending = append(r.pending[:i], r.pending[i+n:]...)
+		if _, ok := ev.(pasteStart); ok {
+			r.pasting = true
+			continue
+		}
+		if ev != nil {
+			return ev
+		}
+	}
+	return nil
+}
+
+// expectedReplyLength returns the length of the expected reply at the beginning of buf or 0 if there is none
+func (r *inputReader) expectedReplyLength(buf []byte) int {
+	for _, match := range r.expected {
+		if start, end := (*match)(buf); start == 0 {
+			return end
+		}
+	}
+	return 0
+}
+
+// query sends the query to the terminal with send and waits for the reply recognised by match. Unlike readReply,
+// it makes sure the reply is not taken for input events if it arrives while the events are being read
+func (r *inputReader) query(send func(), match replyMatcher, timeout time.Duration) ([]byte, error) {
+	r.mu.Lock()
+	if r.term == nil {
+		r.mu.Unlock()
+		return nil, NewScreenError("Cannot read from terminal", ErrNoInput)
+	}
+	r.mu.Unlock()
+	defer r.expect(match)()
+	send()
+	return r.readReply(match, timeout)
+}
+
+// expect registers the reply recognised by match, so that it is kept in the input until it is read with readReply.
+// Replies nobody is waiting for are dropped from the input. Returns the function which unregisters the reply.
+func (r *inputReader) expect(match replyMatcher) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expected = append(r.expected, &match)
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.expected = slices.DeleteFunc(r.expected, func(m *replyMatcher) bool { return m == &match })
+	}
+}
+
+// readReply waits until a reply recognised by match arrives from the terminal, removes it from the input
+// stream and returns it. Any other input received while waiting is kept for later consumption.
+func (r *inputReader) readReply(match replyMatcher, timeout time.Duration) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deadline := time.Now().Add(timeout)
+	buf := make([]byte, inputBufferSize)
+	for {
+		if start, end := match(r.pending); start >= 0 {
+			reply := slices.Clone(r.pending[start:end])
+			r.pending = append(r.pending[:start], r.pending[end:]...)
+			return reply, nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, NewScreenError("Terminal did not reply", ErrTimeout)
+		}
+		n, err := r.read(buf, remaining)
+		if err != nil {
+			return nil, err
+		}
+		r.pending = append(r.pending, buf[:n]...)
+	}
+}
+
+func (r *inputReader) read(buf []byte, timeout time.Duration) (int, error) {
+	if r.term == nil {
+		return 0, NewScreenError("Cannot read from terminal", ErrNoInput)
+	}
+	n, err := r.term.Read(buf, timeout)
+	if err != nil {
+		return 0, NewScreenError("Cannot read from terminal", err)
+	}
+	return n, nil
+}
+
+// matchDelimited returns a replyMatcher for replies that start with prefix and end with suffix
+func matchDelimited(prefix, suffix string) replyMatcher {
+	return func(buf []byte) (int, int) {
+		start := bytes.Index(buf, []byte(prefix))
+		if start < 0 {
+			return -1, -1
+		}
+		end := bytes.Index(buf[start+len(prefix):], []byte(suffix))
+		if end < 0 {
+			return -1, -1
+		}
+		return start, start + len(prefix) + end + len(suffix)
+	}
+}
@@ -0,0 +1,144 @@
+package ansie
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	osc = "\033]"
+	bel = "\a"
+)
+
+type imageSizeUnit int
+
+const (
+	sizeAuto imageSizeUnit = iota
+	sizeCells
+	sizePixels
+	sizePercent
+)
+
+// ImageSize is the width or height of the inline image. Zero value lets the terminal choose the size automatically.
+type ImageSize struct {
+	value int
+	unit  imageSizeUnit
+}
+
+// AutoSize lets the terminal choose the dimension based on the image size and the other dimension
+var AutoSize = ImageSize{}
+
+// Cells creates the image size measured in character cells
+func Cells(n int) ImageSize {
+	return ImageSize{value: n, unit: sizeCells}
+}
+
+// Pixels creates the image size measured in pixels
+func Pixels(n int) ImageSize {
+	return ImageSize{value: n, unit: sizePixels}
+}
+
+// Percent creates the image size measured in percents of the terminal session width or height
+func Percent(n int) ImageSize {
+	return ImageSize{value: n, unit: sizePercent}
+}
+
+func (s ImageSize) String() string {
+	switch s.unit {
+	case sizeCells:
+		return strconv.Itoa(s.value)
+	case sizePixels:
+		return strconv.Itoa(s.value) + "px"
+	case sizePercent:
+		return strconv.Itoa(s.value) + "%"
+	default:
+		return "auto"
+	}
+}
+
+// InlineImage is an image or a file that is sent to the terminal using iTerm2 inline images protocol.
+// The protocol is supported by iTerm2, WezTerm and some other terminals.
+//
+// Data can be in any format the terminal is able to display, like PNG, JPEG or GIF.
+type InlineImage struct {
+	// Name is the file name, it is shown in the download notification or used to save the file
+	Name string
+	// Data is the contents of the file
+	Data []byte
+	// Width of the displayed image
+	Width ImageSize
+	// Height of the displayed image
+	Height ImageSize
+	// PreserveAspectRatio keeps the image proportions when both Width and Height are given
+	PreserveAspectRatio bool
+	// Inline displays the image in the terminal. When false, the file is downloaded instead.
+	Inline bool
+}
+
+// NewInlineImage creates an InlineImage from the file contents. The image is displayed inline
+// at its natural size and preserving its aspect ratio.
+func NewInlineImage(data []byte) *InlineImage {
+	return &InlineImage{
+		Data:                data,
+		PreserveAspectRatio: true,
+		Inline:              true,
+	}
+}
+
+// NewInlineImageFrom creates an InlineImage from the image.Image encoding it as PNG
+func NewInlineImageFrom(img image.Image) (*InlineImage, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return NewInlineImage(buf.Bytes()), nil
+}
+
+// String returns the OSC 1337 sequence that displays or downloads the image
+func (img *InlineImage) String() string {
+	var sb strings.Builder
+	sb.WriteString(osc)
+	sb.WriteString("1337;File=")
+	var args []string
+	if img.Name != "" {
+		args = append(args, "name="+base64.StdEncoding.EncodeToString([]byte(img.Name)))
+	}
+	args = append(args, "size="+strconv.Itoa(len(img.Data)))
+	if img.Width != AutoSize {
+		args = append(args, "width="+img.Width.String())
+	}
+	if img.Height != AutoSize {
+		args = append(args, "height="+img.Height.String())
+	}
+	if !img.PreserveAspectRatio {
+		args = append(args, "preserveAspectRatio=0")
+	}
+	if img.Inline {
+		args = append(args, "inline=1")
+	}
+	sb.WriteString(strings.Join(args, ";"))
+	sb.WriteRune(':')
+	sb.WriteString(base64.StdEncoding.EncodeToString(img.Data))
+	sb.WriteString(bel)
+	return sb.String()
+}
+
+// WriteTo writes the image sequence to w
+func (img *InlineImage) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, img.String())
+	return int64(n), err
+}
+
+// InlineImage adds the image using iTerm2 inline images protocol to the AnsiBuffer's buffer.
+// Nothing is added if the colour output is disabled.
+func (ap *AnsiBuffer) InlineImage(img *InlineImage) *AnsiBuffer {
+	if ap.enabled {
+		ap.content.WriteString(img.String())
+	}
+	return ap
+}
@@ -0,0 +1,60 @@
+package ansie
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestInlineImage_String(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	img := NewInlineImage([]byte("data"))
+	g.Expect(img.String()).To(Equal("\033]1337;File=size=4;inline=1:ZGF0YQ==\a"))
+
+	img.Name = "pic.png"
+	img.Width = Cells(20)
+	img.Height = Percent(50)
+	img.PreserveAspectRatio = false
+	img.Inline = false
+	g.Expect(img.String()).To(Equal("\033]1337;File=name=" + base64.StdEncoding.EncodeToString([]byte("pic.png")) +
+		";size=4;width=20;height=50%;preserveAspectRatio=0:ZGF0YQ==\a"))
+}
+
+func TestImageSize_String(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(AutoSize.String()).To(Equal("auto"))
+	g.Expect(Cells(3).String()).To(Equal("3"))
+	g.Expect(Pixels(120).String()).To(Equal("120px"))
+	g.Expect(Percent(100).String()).To(Equal("100%"))
+}
+
+func TestNewInlineImageFrom(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	img, err := NewInlineImageFrom(testImage(2, 2))
+	g.Expect(err).To(BeNil())
+	g.Expect(img.Data).To(HavePrefix("\x89PNG"))
+
+	var buf bytes.Buffer
+	n, err := img.WriteTo(&buf)
+	g.Expect(err).To(BeNil())
+	g.Expect(n).To(Equal(int64(buf.Len())))
+	g.Expect(buf.String()).To(Equal(img.String()))
+}
+
+func TestAnsiBuffer_InlineImage(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	img := NewInlineImage([]byte("data"))
+	a := NewAnsi()
+	s := a.A("before ").InlineImage(img).A(" after").String()
+	g.Expect(s).To(Equal("before " + img.String() + " after"))
+
+	a.SetEnabled(false)
+	s = a.A("before ").InlineImage(img).A(" after").String()
+	g.Expect(s).To(Equal("before  after"))
+}
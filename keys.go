@@ -0,0 +1,385 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Event is an input event received from the terminal, like a key press
+type Event interface {
+	isEvent()
+}
+
+// Key identifies a key of the keyboard. Keys producing characters are reported as KeyRune
+type Key int
+
+const (
+	// KeyRune is a key producing a character, the character is in the Rune field of the KeyEvent
+	KeyRune Key = iota
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyInsert
+	KeyDelete
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyF13
+	KeyF14
+	KeyF15
+	KeyF16
+	KeyF17
+	KeyF18
+	KeyF19
+	KeyF20
+	KeyF21
+	KeyF22
+	KeyF23
+	KeyF24
+)
+
+var keyNames = map[Key]string{
+	KeyEnter:     "Enter",
+	KeyTab:       "Tab",
+	KeyBackspace: "Backspace",
+	KeyEscape:    "Esc",
+	KeyUp:        "Up",
+	KeyDown:      "Down",
+	KeyRight:     "Right",
+	KeyLeft:      "Left",
+	KeyHome:      "Home",
+	KeyEnd:       "End",
+	KeyPageUp:    "PgUp",
+	KeyPageDown:  "PgDn",
+	KeyInsert:    "Insert",
+	KeyDelete:    "Delete",
+}
+
+// String returns the name of the key, like "Enter" or "F5"
+func (k Key) String() string {
+	if k >= KeyF1 && k <= KeyF24 {
+		return "F" + strconv.Itoa(int(k-KeyF1)+1)
+	}
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	if k == KeyRune {
+		return "Rune"
+	}
+	return "Key(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Modifier is a set of modifier keys held when the key was pressed. The values match the bits of xterm
+// modifier parameter. Hyper, CapsLock and NumLock are only reported with kitty keyboard protocol
+type Modifier uint8
+
+const (
+	ModShift Modifier = 1 << iota
+	ModAlt
+	ModCtrl
+	// ModMeta is the Meta or Super key
+	ModMeta
+	ModHyper
+	_
+	ModCapsLock
+	ModNumLock
+)
+
+// KeyAction is the kind of the key event. Only key presses are reported, unless kitty keyboard protocol is
+// enabled with KittyReportEventTypes flag
+type KeyAction uint8
+
+const (
+	KeyPress KeyAction = iota
+	KeyRepeat
+	KeyRelease
+)
+
+// KeyEvent is a key press. Ctrl combinations with letters are reported as the lowercase letter with ModCtrl,
+// characters typed with Shift are reported as the shifted character without ModShift
+type KeyEvent struct {
+	Key  Key
+	Rune rune
+	Mod  Modifier
+	// Action is KeyPress unless the terminal reports repeats and releases, see EnableKittyKeyboard
+	Action KeyAction
+	// ShiftedRune and BaseRune are the alternate keys reported by kitty keyboard protocol: the character
+	// the key produces with Shift and the character of the key in the standard US layout
+	ShiftedRune rune
+	BaseRune    rune
+	// Text is the text produced by the key, reported by kitty keyboard protocol
+	Text string
+}
+
+func (KeyEvent) isEvent() {}
+
+// String returns a human-readable description of the key press, like "Ctrl+Alt+x" or "Shift+F5"
+func (e KeyEvent) String() string {
+	var sb strings.Builder
+	for _, m := range []struct {
+		mod  Modifier
+		name string
+	}{{ModCtrl, "Ctrl+"}, {ModAlt, "Alt+"}, {ModShift, "Shift+"}, {ModMeta, "Meta+"}} {
+		if e.Mod&m.mod != 0 {
+			sb.WriteString(m.name)
+		}
+	}
+	switch {
+	case e.Key != KeyRune:
+		sb.WriteString(e.Key.String())
+	case e.Rune == ' ':
+		sb.WriteString("Space")
+	default:
+		sb.WriteRune(e.Rune)
+	}
+	return sb.String()
+}
+
+// terminalReply is a sequence sent by the terminal in reply to a query, it is left in the input stream
+// for the method that sent the query
+type terminalReply struct{}
+
+func (terminalReply) isEvent() {}
+
+// csiKeys are the keys of "CSI 1;m X" and "SS3 X" sequences
+var csiKeys = map[byte]Key{
+	'A': KeyUp, 'B': KeyDown, 'C': KeyRight, 'D': KeyLeft, 'H': KeyHome, 'F': KeyEnd,
+	'P': KeyF1, 'Q': KeyF2, 'R': KeyF3, 'S': KeyF4,
+}
+
+// tildeKeys are the keys of "CSI n;m ~" sequences. F13-F20 are VT220 codes, F21-F24 continue them as 42-45, as sent
+// by some terminals. xterm reports F13-F24 as F1-F12 with Shift or Ctrl instead, they are decoded as such
+var tildeKeys = map[int]Key{
+	1: KeyHome, 2: KeyInsert, 3: KeyDelete, 4: KeyEnd, 5: KeyPageUp, 6: KeyPageDown, 7: KeyHome, 8: KeyEnd,
+	11: KeyF1, 12: KeyF2, 13: KeyF3, 14: KeyF4, 15: KeyF5, 17: KeyF6, 18: KeyF7, 19: KeyF8, 20: KeyF9,
+	21: KeyF10, 23: KeyF11, 24: KeyF12, 25: KeyF13, 26: KeyF14, 28: KeyF15, 29: KeyF16, 31: KeyF17,
+	32: KeyF18, 33: KeyF19, 34: KeyF20, 42: KeyF21, 43: KeyF22, 44: KeyF23, 45: KeyF24,
+}
+
+// decodeEvent decodes the first event in buf and returns it with the number of bytes it occupies.
+// If buf contains an incomplete sequence, it returns zero length, unless final is true, meaning that no more
+// input is expected soon. In that case the sequence is decoded as separate keys, so the lone ESC is reported
+// as Esc key and ESC followed by a character as Alt combination.
+// Sequences that are not recognised are skipped and returned as nil events
+func decodeEvent(buf []byte, final bool) (Event, int) {
+	if len(buf) == 0 {
+		return nil, 0
+	}
+	if buf[0] == 0x1b {
+		return decodeEscape(buf, final)
+	}
+	return decodeChar(buf, final)
+}
+
+func decodeChar(buf []byte, final bool) (Event, int) {
+	b := buf[0]
+	switch {
+	case b == '\r' || b == '\n':
+		return KeyEvent{Key: KeyEnter}, 1
+	case b == '\t':
+		return KeyEvent{Key: KeyTab}, 1
+	case b == 0x7f:
+		return KeyEvent{Key: KeyBackspace}, 1
+	case b == 0:
+		return KeyEvent{Rune: ' ', Mod: ModCtrl}, 1
+	case b < 0x1b:
+		return KeyEvent{Rune: rune('a' + b - 1), Mod: ModCtrl}, 1
+	case b < 0x20:
+		return KeyEvent{Rune: rune(b + 0x40), Mod: ModCtrl}, 1
+	}
+	if !utf8.FullRune(buf) {
+		if !final {
+			return nil, 0
+		}
+		return KeyEvent{Rune: utf8.RuneError}, 1
+	}
+	r, size := utf8.DecodeRune(buf)
+	return KeyEvent{Rune: r}, size
+}
+
+func decodeEscape(buf []byte, final bool) (Event, int) {
+	if len(buf) == 1 {
+		if final {
+			return KeyEvent{Key: KeyEscape}, 1
+		}
+		return nil, 0
+	}
+	var ev Event
+	var n int
+	switch buf[1] {
+	case '[':
+		ev, n = decodeCsi(buf)
+	case 'O':
+		ev, n = decodeSs3(buf)
+	case ']', 'P', '_':
+		n = stringSequenceLength(buf)
+		ev = terminalReply{}
+	case 0x1b:
+		ev, n = decodeEscape(buf[1:], final)
+		if n > 0 {
+			return withAlt(ev), n + 1
+		}
+		return nil, 0
+	}
+	switch {
+	case n > 0:
+		return ev, n
+	case n < 0 && !final:
+		return nil, 0
+	}
+	// not a sequence, ESC is sent with the character when it is typed with Alt
+	ev, n = decodeChar(buf[1:], final)
+	if n == 0 {
+		return nil, 0
+	}
+	return withAlt(ev), n + 1
+}
+
+// decodeCsi decodes "CSI params final" sequence. It returns negative length if the sequence is incomplete and zero
+// length if the bytes are not a valid sequence
+func decodeCsi(buf []byte) (Event, int) {
+	i := 2
+	for i < len(buf) && buf[i] >= 0x30 && buf[i] <= 0x3f {
+		i++
+	}
+	paramsEnd := i
+	for i < len(buf) && buf[i] >= 0x20 && buf[i] <= 0x2f {
+		i++
+	}
+	if i == len(buf) {
+		return nil, -1
+	}
+	if buf[i] < 0x40 || buf[i] > 0x7e {
+		return nil, 0
+	}
+	params, final := string(buf[2:paramsEnd]), buf[i]
+	n := i + 1
+	if final == 'M' && i == 2 {
+		// X10 mouse report is followed by three bytes
+		if len(buf) < n+3 {
+			return nil, -1
+		}
+		return decodeX10Mouse(buf[n : n+3]), n + 3
+	}
+	if final == 'M' || (final == 'm' && strings.HasPrefix(params, "<")) {
+		if ev, ok := decodeMouseParams(params, final); ok {
+			return ev, n
+		}
+	}
+	if params != "" && strings.ContainsAny(params[:1], "<=>?") {
+		// private sequences are replies to the queries
+		return terminalReply{}, n
+	}
+	if final == 'u' {
+		return decodeKittyKey(params), n
+	}
+	parts := strings.Split(params, ";")
+	mod, action := modifierParam(parts)
+	if key, ok := csiKeys[final]; ok {
+		return KeyEvent{Key: key, Mod: mod, Action: action}, n
+	}
+	switch final {
+	case 'I', 'O':
+		if params == "" {
+			return FocusEvent{Focused: final == 'I'}, n
+		}
+	case 'Z':
+		return KeyEvent{Key: KeyTab, Mod: ModShift}, n
+	case '~':
+		code, _ := strconv.Atoi(parts[0])
+		if code == 200 {
+			return pasteStart{}, n
+		}
+		if key, ok := tildeKeys[code]; ok {
+			return KeyEvent{Key: key, Mod: mod, Action: action}, n
+		}
+	}
+	return nil, n
+}
+
+// decodeSs3 decodes "SS3 final" sequence, some terminals add modifier parameter before the final character.
+// It returns negative length if the sequence is incomplete
+func decodeSs3(buf []byte) (Event, int) {
+	i := 2
+	for i < len(buf) && buf[i] >= '0' && buf[i] <= '9' {
+		i++
+	}
+	if i == len(buf) {
+		return nil, -1
+	}
+	mod, _ := modifierParam([]string{"", string(buf[2:i])})
+	if key, ok := csiKeys[buf[i]]; ok {
+		return KeyEvent{Key: key, Mod: mod}, i + 1
+	}
+	if buf[i] == 'M' {
+		return KeyEvent{Key: KeyEnter, Mod: mod}, i + 1
+	}
+	return nil, i + 1
+}
+
+// stringSequenceLength returns the length of OSC, DCS or APC sequence terminated with BEL or ST,
+// or negative value if the sequence is incomplete
+func stringSequenceLength(buf []byte) int {
+	for i := 2; i < len(buf); i++ {
+		if buf[i] == '\a' {
+			return i + 1
+		}
+		if buf[i] == 0x1b && i+1 < len(buf) && buf[i+1] == '\\' {
+			return i + 2
+		}
+	}
+	return -1
+}
+
+// modifierParam returns the modifiers encoded in the second parameter as 1 + modifier bits, optionally followed
+// by the event type: "m:type"
+func modifierParam(params []string) (Modifier, KeyAction) {
+	if len(params) < 2 {
+		return 0, KeyPress
+	}
+	value, eventType, _ := strings.Cut(params[1], ":")
+	var action KeyAction
+	if t, err := strconv.Atoi(eventType); err == nil && t >= 1 && t <= 3 {
+		action = KeyAction(t - 1)
+	}
+	m, err := strconv.Atoi(value)
+	if err != nil || m < 1 {
+		return 0, action
+	}
+	m--
+	// kitty keyboard protocol reports Super as 8 and Meta as 32, both are reported as ModMeta
+	mod := Modifier(m &^ 32)
+	if m&32 != 0 {
+		mod |= ModMeta
+	}
+	return mod, action
+}
+
+func withAlt(ev Event) Event {
+	if key, ok := ev.(KeyEvent); ok {
+		key.Mod |= ModAlt
+		return key
+	}
+	return ev
+}
@@ -0,0 +1,125 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDecodeEvent_Keys(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := map[string]KeyEvent{
+		"a":          {Rune: 'a'},
+		"Ж":          {Rune: 'Ж'},
+		"\r":         {Key: KeyEnter},
+		"\t":         {Key: KeyTab},
+		"\x7f":       {Key: KeyBackspace},
+		"\x01":       {Rune: 'a', Mod: ModCtrl},
+		"\x00":       {Rune: ' ', Mod: ModCtrl},
+		"\x1c":       {Rune: '\\', Mod: ModCtrl},
+		"\033x":      {Rune: 'x', Mod: ModAlt},
+		"\033\x01":   {Rune: 'a', Mod: ModCtrl | ModAlt},
+		"\033[A":     {Key: KeyUp},
+		"\033[1;5D":  {Key: KeyLeft, Mod: ModCtrl},
+		"\033[1;4H":  {Key: KeyHome, Mod: ModShift | ModAlt},
+		"\033OF":     {Key: KeyEnd},
+		"\033OP":     {Key: KeyF1},
+		"\033O5S":    {Key: KeyF4, Mod: ModCtrl},
+		"\033[1;2Q":  {Key: KeyF2, Mod: ModShift},
+		"\033[Z":     {Key: KeyTab, Mod: ModShift},
+		"\033[2~":    {Key: KeyInsert},
+		"\033[3;3~":  {Key: KeyDelete, Mod: ModAlt},
+		"\033[5~":    {Key: KeyPageUp},
+		"\033[6~":    {Key: KeyPageDown},
+		"\033[15~":   {Key: KeyF5},
+		"\033[24;6~": {Key: KeyF12, Mod: ModShift | ModCtrl},
+		"\033[34~":   {Key: KeyF20},
+		"\033[42~":   {Key: KeyF21},
+		"\033[43;5~": {Key: KeyF22, Mod: ModCtrl},
+		"\033[44~":   {Key: KeyF23},
+		"\033[45~":   {Key: KeyF24},
+		"\033\033[B": {Key: KeyDown, Mod: ModAlt},
+		"\033\033":   {Key: KeyEscape, Mod: ModAlt},
+		"\033OM":     {Key: KeyEnter},
+		"\033[1;9A":  {Key: KeyUp, Mod: ModMeta},
+	}
+	for input, expected := range cases {
+		ev, n := decodeEvent([]byte(input), true)
+		g.Expect(ev).To(Equal(expected), "Unexpected event for %q", input)
+		g.Expect(n).To(Equal(len(input)), "Expected the whole input %q to be decoded", input)
+	}
+
+	ev, n := decodeEvent([]byte("\033]1;title"), true)
+	g.Expect(ev).To(Equal(KeyEvent{Rune: ']', Mod: ModAlt}), "Expected incomplete sequence to be decoded as keys")
+	g.Expect(n).To(Equal(2))
+}
+
+func TestDecodeEvent_Incomplete(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, input := range []string{"\033", "\033[", "\033[1;5", "\033O", "\033\033", "\xd0", "\033]0;title"} {
+		ev, n := decodeEvent([]byte(input), false)
+		g.Expect(n).To(Equal(0), "Expected %q to be incomplete", input)
+		g.Expect(ev).To(BeNil())
+	}
+	ev, n := decodeEvent([]byte("\033"), true)
+	g.Expect(ev).To(Equal(KeyEvent{Key: KeyEscape}))
+	g.Expect(n).To(Equal(1))
+
+	ev, n = decodeEvent([]byte("\033[99~x"), false)
+	g.Expect(ev).To(BeNil(), "Expected unknown sequence to be skipped")
+	g.Expect(n).To(Equal(5))
+}
+
+func TestKeyEvent_String(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(KeyEvent{Rune: 'x', Mod: ModCtrl | ModAlt}.String()).To(Equal("Ctrl+Alt+x"))
+	g.Expect(KeyEvent{Key: KeyF5, Mod: ModShift}.String()).To(Equal("Shift+F5"))
+	g.Expect(KeyEvent{Rune: ' '}.String()).To(Equal("Space"))
+	g.Expect(KeyEvent{Key: KeyPageDown}.String()).To(Equal("PgDn"))
+}
+
+func TestScreen_ReadEvent(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+	defer s.Close()
+
+	m.SendInput("a\033[A\033_Gi=1;OK\033\\")
+	ev, err := s.ReadEvent(time.Second)
+	g.Expect(err).To(BeNil())
+	g.Expect(ev).To(Equal(KeyEvent{Rune: 'a'}))
+	ev, _ = s.ReadEvent(time.Second)
+	g.Expect(ev).To(Equal(KeyEvent{Key: KeyUp}))
+
+	ev, _ = s.ReadEvent(10 * time.Millisecond)
+	g.Expect(ev).To(BeNil(), "Expected timeout")
+	g.Expect(s.input.pending).To(BeEmpty(), "Expected reply nobody waits for to be dropped")
+
+	unexpect := s.input.expect(matchKittyReply(1))
+	m.SendInput("\033_Gi=1;OK\033\\b")
+	ev, _ = s.ReadEvent(time.Second)
+	g.Expect(ev).To(Equal(KeyEvent{Rune: 'b'}))
+	g.Expect(string(s.input.pending)).To(Equal("\033_Gi=1;OK\033\\"), "Expected reply to be kept for the query")
+	unexpect()
+	s.input.pending = nil
+
+	s.SetEscapeTimeout(20 * time.Millisecond)
+	m.SendInput("\033")
+	start := time.Now()
+	ev, _ = s.ReadEvent(time.Second)
+	g.Expect(ev).To(Equal(KeyEvent{Key: KeyEscape}))
+	g.Expect(time.Since(start)).To(BeNumerically(">=", 20*time.Millisecond))
+
+	m.SendInput("\033")
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		m.SendInput("[B")
+	}()
+	ev, _ = s.ReadEvent(time.Second)
+	g.Expect(ev).To(Equal(KeyEvent{Key: KeyDown}), "Expected split sequence to be joined")
+}
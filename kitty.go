@@ -0,0 +1,309 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	kittyApcStart      = "\033_G"
+	kittyApcEnd        = "\033\\"
+	kittyReplyOK       = "OK"
+	kittyChunkSize     = 4096
+	kittyDefaultWait   = 2 * time.Second
+	kittyQueryImageID  = 0x7fffffff
+	kittyQueryPayload  = "AAAA"
+	kittyQueryTimeout  = 500 * time.Millisecond
+	kittyFormatRGBA    = 32
+	kittyFormatPNG     = 100
+	kittyDeleteQuietly = 2
+)
+
+// KittyImageFormat defines how the image data is transmitted to the terminal
+type KittyImageFormat int
+
+const (
+	// KittyPNG transmits the image encoded as PNG. This is the most compact format for most images.
+	KittyPNG KittyImageFormat = iota
+	// KittyRGBA transmits raw 32-bit RGBA pixels. It is larger, but doesn't require the terminal to decode PNG.
+	KittyRGBA
+)
+
+// KittyImageOptions control how the image is transmitted and placed on the screen using the kitty graphics protocol.
+// All fields are optional, zero value places the whole image at its natural size.
+type KittyImageOptions struct {
+	// ImageID is the identifier of the image. If zero, the new identifier is allocated automatically.
+	ImageID uint32
+	// PlacementID identifies the placement of the image, allowing the same image to be displayed
+	// several times and each placement to be moved or deleted independently.
+	PlacementID uint32
+	// Format of the transmitted image data
+	Format KittyImageFormat
+	// ZIndex defines the vertical stacking order of the image. Negative values draw the image below the text.
+	ZIndex int32
+	// Crop is the rectangle of the source image, in pixels, to display. Empty rectangle displays the whole image.
+	Crop image.Rectangle
+	// Columns and Rows scale the image to cover the given number of cells. Zero keeps the natural size.
+	Columns int
+	Rows    int
+	// OffsetX and OffsetY are pixel offsets of the image inside the first cell.
+	OffsetX int
+	OffsetY int
+	// KeepCursor prevents the terminal from moving the cursor after the image is placed.
+	KeepCursor bool
+	// Quiet suppresses terminal replies. The errors reported by the terminal will not be detected.
+	Quiet bool
+	// Timeout is the maximum time to wait for the terminal reply. Defaults to 2 seconds.
+	Timeout time.Duration
+}
+
+// KittyError is an error reported by the terminal in reply to a kitty graphics command
+type KittyError struct {
+	ImageID uint32
+	// Code is the error code, such as ENOENT or EINVAL
+	Code string
+	// Message is the human-readable description of the error, if the terminal provided one
+	Message string
+}
+
+func (err KittyError) Error() string {
+	if err.Message == "" {
+		return fmt.Sprintf("kitty graphics error for image %d: %s", err.ImageID, err.Code)
+	}
+	return fmt.Sprintf("kitty graphics error for image %d: %s: %s", err.ImageID, err.Code, err.Message)
+}
+
+// SupportsKittyGraphics queries the terminal to check if it implements the kitty graphics protocol.
+// Terminals that don't support the protocol don't reply, so this method may block for up to half a second.
+func (s *Screen) SupportsKittyGraphics() bool {
+	reply, err := s.input.query(func() {
+		s.write(kittyApcStart + fmt.Sprintf("i=%d,s=1,v=1,a=q,t=d,f=24;%s", kittyQueryImageID, kittyQueryPayload) + kittyApcEnd)
+	}, matchKittyReply(kittyQueryImageID), kittyQueryTimeout)
+	if err != nil {
+		return false
+	}
+	_, message := parseKittyReply(reply)
+	return message == kittyReplyOK
+}
+
+// DrawImage transmits the image to the terminal and displays it with the top-left corner in the cell (x, y).
+// Coordinates are 1-based, where (1, 1) is the top-left corner.
+// Returns the identifier of the image, which can be used to place the image again without re-transmitting it.
+func (s *Screen) DrawImage(img image.Image, x, y int, opts *KittyImageOptions) (uint32, error) {
+	if err := s.checkPosition(x, y); err != nil {
+		return 0, err
+	}
+	s.MoveCursorTo(x, y)
+	return s.transmitImage(img, "T", opts)
+}
+
+// TransmitImage sends the image data to the terminal without displaying it.
+// Use PlaceImage with the returned identifier to display the image.
+func (s *Screen) TransmitImage(img image.Image, opts *KittyImageOptions) (uint32, error) {
+	return s.transmitImage(img, "t", opts)
+}
+
+// PlaceImage displays an image that was already transmitted to the terminal in the cell (x, y).
+// PlacementID, ZIndex, Crop, size and offset options apply to the new placement.
+func (s *Screen) PlaceImage(imageID uint32, x, y int, opts *KittyImageOptions) error {
+	if err := s.checkPosition(x, y); err != nil {
+		return err
+	}
+	if opts == nil {
+		opts = &KittyImageOptions{}
+	}
+	keys := []string{"a=p", fmt.Sprintf("i=%d", imageID)}
+	keys = append(keys, opts.placementKeys()...)
+	if opts.Quiet {
+		keys = append(keys, "q=2")
+	}
+	s.MoveCursorTo(x, y)
+	command := kittyApcStart + strings.Join(keys, ",") + kittyApcEnd
+	if opts.Quiet {
+		s.write(command)
+		return nil
+	}
+	return s.kittyCommand(command, imageID, opts.timeout())
+}
+
+// DeleteImage removes all placements of the image from the screen. If free is true, the image data is also
+// released by the terminal and the image cannot be placed again without re-transmitting it.
+func (s *Screen) DeleteImage(imageID uint32, free bool) {
+	what := "i"
+	if free {
+		what = "I"
+	}
+	s.write(fmt.Sprintf("%sa=d,d=%s,i=%d,q=%d%s", kittyApcStart, what, imageID, kittyDeleteQuietly, kittyApcEnd))
+}
+
+// DeletePlacement removes a single placement of the image from the screen
+func (s *Screen) DeletePlacement(imageID, placementID uint32) {
+	s.write(fmt.Sprintf("%sa=d,d=i,i=%d,p=%d,q=%d%s", kittyApcStart, imageID, placementID, kittyDeleteQuietly, kittyApcEnd))
+}
+
+// DeleteAllImages removes all images from the screen. If free is true, the image data is released by the terminal.
+func (s *Screen) DeleteAllImages(free bool) {
+	what := "a"
+	if free {
+		what = "A"
+	}
+	s.write(fmt.Sprintf("%sa=d,d=%s,q=%d%s", kittyApcStart, what, kittyDeleteQuietly, kittyApcEnd))
+}
+
+func (s *Screen) transmitImage(img image.Image, action string, opts *KittyImageOptions) (uint32, error) {
+	if opts == nil {
+		opts = &KittyImageOptions{}
+	}
+	imageID := opts.ImageID
+	if imageID == 0 {
+		imageID = s.nextImageID.Add(1)
+	}
+	keys := []string{"a=" + action, fmt.Sprintf("i=%d", imageID)}
+	var payload []byte
+	switch opts.Format {
+	case KittyRGBA:
+		bounds := img.Bounds()
+		rgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+		keys = append(keys, fmt.Sprintf("f=%d", kittyFormatRGBA), fmt.Sprintf("s=%d", bounds.Dx()), fmt.Sprintf("v=%d", bounds.Dy()))
+		payload = rgba.Pix
+	default:
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return 0, NewScreenError("Cannot encode image", err)
+		}
+		keys = append(keys, fmt.Sprintf("f=%d", kittyFormatPNG))
+		payload = buf.Bytes()
+	}
+	if action == "T" {
+		keys = append(keys, opts.placementKeys()...)
+	}
+	if opts.Quiet {
+		keys = append(keys, "q=2")
+	}
+	command := encodeKittyChunks(strings.Join(keys, ","), payload, opts.Quiet)
+	if opts.Quiet {
+		s.write(command)
+		return imageID, nil
+	}
+	return imageID, s.kittyCommand(command, imageID, opts.timeout())
+}
+
+// kittyCommand writes the command to the terminal and waits for its reply
+func (s *Screen) kittyCommand(command string, imageID uint32, timeout time.Duration) error {
+	reply, err := s.input.query(func() { s.write(command) }, matchKittyReply(imageID), timeout)
+	if err != nil {
+		return err
+	}
+	_, message := parseKittyReply(reply)
+	if message == kittyReplyOK {
+		return nil
+	}
+	code, text, _ := strings.Cut(message, ":")
+	return KittyError{ImageID: imageID, Code: code, Message: text}
+}
+
+func (opts *KittyImageOptions) placementKeys() []string {
+	var keys []string
+	if opts.PlacementID != 0 {
+		keys = append(keys, fmt.Sprintf("p=%d", opts.PlacementID))
+	}
+	if opts.ZIndex != 0 {
+		keys = append(keys, fmt.Sprintf("z=%d", opts.ZIndex))
+	}
+	if !opts.Crop.Empty() {
+		keys = append(keys, fmt.Sprintf("x=%d", opts.Crop.Min.X), fmt.Sprintf("y=%d", opts.Crop.Min.Y),
+			fmt.Sprintf("w=%d", opts.Crop.Dx()), fmt.Sprintf("h=%d", opts.Crop.Dy()))
+	}
+	if opts.Columns > 0 {
+		keys = append(keys, fmt.Sprintf("c=%d", opts.Columns))
+	}
+	if opts.Rows > 0 {
+		keys = append(keys, fmt.Sprintf("r=%d", opts.Rows))
+	}
+	if opts.OffsetX > 0 {
+		keys = append(keys, fmt.Sprintf("X=%d", opts.OffsetX))
+	}
+	if opts.OffsetY > 0 {
+		keys = append(keys, fmt.Sprintf("Y=%d", opts.OffsetY))
+	}
+	if opts.KeepCursor {
+		keys = append(keys, "C=1")
+	}
+	return keys
+}
+
+func (opts *KittyImageOptions) timeout() time.Duration {
+	if opts.Timeout <= 0 {
+		return kittyDefaultWait
+	}
+	return opts.Timeout
+}
+
+// encodeKittyChunks encodes the payload as base64 and splits it into APC sequences of at most 4096 bytes.
+// Only the first sequence carries the control keys, all sequences except the last one are marked with m=1.
+func encodeKittyChunks(control string, payload []byte, quiet bool) string {
+	encoded := base64.StdEncoding.EncodeToString(payload)
+	var sb strings.Builder
+	for first := true; first || len(encoded) > 0; first = false {
+		chunk := encoded[:min(kittyChunkSize, len(encoded))]
+		encoded = encoded[len(chunk):]
+		more := 0
+		if len(encoded) > 0 {
+			more = 1
+		}
+		sb.WriteString(kittyApcStart)
+		if first {
+			sb.WriteString(control)
+			sb.WriteRune(',')
+		} else if quiet {
+			sb.WriteString("q=2,")
+		}
+		sb.WriteString("m=")
+		sb.WriteString(strconv.Itoa(more))
+		sb.WriteRune(';')
+		sb.WriteString(chunk)
+		sb.WriteString(kittyApcEnd)
+	}
+	return sb.String()
+}
+
+// matchKittyReply returns a replyMatcher for the terminal reply to the command for the given image
+func matchKittyReply(imageID uint32) replyMatcher {
+	prefix := []byte(fmt.Sprintf("%si=%d", kittyApcStart, imageID))
+	return func(buf []byte) (int, int) {
+		offset := 0
+		for {
+			start := bytes.Index(buf[offset:], prefix)
+			if start < 0 {
+				return -1, -1
+			}
+			start += offset
+			next := start + len(prefix)
+			if next < len(buf) && (buf[next] == ',' || buf[next] == ';') {
+				end := bytes.Index(buf[next:], []byte(kittyApcEnd))
+				if end < 0 {
+					return -1, -1
+				}
+				return start, next + end + len(kittyApcEnd)
+			}
+			offset = next
+		}
+	}
+}
+
+// parseKittyReply splits the reply into control keys and the message
+func parseKittyReply(reply []byte) (keys string, message string) {
+	body := strings.TrimSuffix(strings.TrimPrefix(string(reply), kittyApcStart), kittyApcEnd)
+	keys, message, _ = strings.Cut(body, ";")
+	return keys, message
+}
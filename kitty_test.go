@@ -0,0 +1,130 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"errors"
+	"image"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestScreen_DrawImage(t *testing.T) {
+	g := NewGomegaWithT(t)
+	m := NewMockTerminal(80, 24)
+	s, err := NewScreenFromTerminal(m)
+	g.Expect(err).To(BeNil())
+	defer s.Close()
+	m.ResetBuffer()
+
+	m.SendInput("\033_Gi=1;OK\033\\")
+	id, err := s.DrawImage(testImage(4, 4), 5, 3, &KittyImageOptions{PlacementID: 7, ZIndex: -1})
+	g.Expect(err).To(BeNil())
+	g.Expect(id).To(Equal(uint32(1)))
+	out := m.Buffer.String()
+	g.Expect(out).To(HavePrefix("\033[3;5H"), "Expected cursor to move to the image position")
+	g.Expect(out).To(ContainSubstring("\033_Ga=T,i=1,f=100,p=7,z=-1,m=0;"))
+	g.Expect(out).To(HaveSuffix("\033\\"))
+}
+
+func TestScreen_DrawImageChunked(t *testing.T) {
+	g := NewGomegaWithT(t)
+	m := NewMockTerminal(80, 24)
+	s, err := NewScreenFromTerminal(m)
+	g.Expect(err).To(BeNil())
+	defer s.Close()
+	m.ResetBuffer()
+
+	_, err = s.DrawImage(testImage(64, 64), 1, 1, &KittyImageOptions{Format: KittyRGBA, Quiet: true})
+	g.Expect(err).To(BeNil())
+	out := m.Buffer.String()
+	g.Expect(out).To(ContainSubstring("\033_Ga=T,i=1,f=32,s=64,v=64,q=2,m=1;"))
+	g.Expect(out).To(ContainSubstring("\033_Gq=2,m=0;"))
+	chunks := strings.Count(out, "\033_G")
+	g.Expect(chunks).To(Equal(6), "Expected 16KiB of RGBA data to be sent in 6 base64 chunks")
+	for _, chunk := range strings.Split(out, "\033\\") {
+		_, command, _ := strings.Cut(chunk, "\033_G")
+		if _, payload, found := strings.Cut(command, ";"); found {
+			g.Expect(len(payload)).To(BeNumerically("<=", kittyChunkSize))
+		}
+	}
+}
+
+func TestScreen_DrawImageError(t *testing.T) {
+	g := NewGomegaWithT(t)
+	m := NewMockTerminal(80, 24)
+	s, err := NewScreenFromTerminal(m)
+	g.Expect(err).To(BeNil())
+	defer s.Close()
+
+	m.SendInput("x\033_Gi=42;EINVAL:bad image\033\\y")
+	_, err = s.DrawImage(testImage(2, 2), 1, 1, &KittyImageOptions{ImageID: 42})
+	g.Expect(err).To(Equal(KittyError{ImageID: 42, Code: "EINVAL", Message: "bad image"}))
+	g.Expect(string(s.input.pending)).To(Equal("xy"), "Expected keystrokes around the reply to be preserved")
+
+	_, err = s.DrawImage(testImage(2, 2), 81, 1, nil)
+	g.Expect(err).ToNot(BeNil(), "Expected an error when drawing outside of the screen")
+}
+
+func TestScreen_DrawImageTimeout(t *testing.T) {
+	g := NewGomegaWithT(t)
+	m := NewMockTerminal(80, 24)
+	s, err := NewScreenFromTerminal(m)
+	g.Expect(err).To(BeNil())
+	defer s.Close()
+
+	_, err = s.DrawImage(testImage(2, 2), 1, 1, &KittyImageOptions{Timeout: 10 * time.Millisecond})
+	g.Expect(errors.Is(err, ErrTimeout)).To(BeTrue())
+
+	m.SendInput("\033_Gi=1;OK\033\\x")
+	ev, err := s.ReadEvent(time.Second)
+	g.Expect(err).To(BeNil())
+	g.Expect(ev).To(Equal(KeyEvent{Rune: 'x'}))
+	g.Expect(s.input.pending).To(BeEmpty(), "Expected late reply to be dropped")
+}
+
+func TestScreen_PlaceAndDeleteImage(t *testing.T) {
+	g := NewGomegaWithT(t)
+	m := NewMockTerminal(80, 24)
+	s, err := NewScreenFromTerminal(m)
+	g.Expect(err).To(BeNil())
+	defer s.Close()
+	m.ResetBuffer()
+
+	m.SendInput("\033_Gi=3,p=1;OK\033\\")
+	err = s.PlaceImage(3, 2, 2, &KittyImageOptions{PlacementID: 1, Crop: image.Rect(1, 2, 5, 6), Columns: 10, KeepCursor: true})
+	g.Expect(err).To(BeNil())
+	g.Expect(m.Buffer.String()).To(Equal("\033[2;2H\033_Ga=p,i=3,p=1,x=1,y=2,w=4,h=4,c=10,C=1\033\\"))
+
+	m.ResetBuffer()
+	s.DeletePlacement(3, 1)
+	s.DeleteImage(3, true)
+	s.DeleteAllImages(false)
+	g.Expect(m.Buffer.String()).To(Equal("\033_Ga=d,d=i,i=3,p=1,q=2\033\\\033_Ga=d,d=I,i=3,q=2\033\\\033_Ga=d,d=a,q=2\033\\"))
+}
+
+func TestScreen_SupportsKittyGraphics(t *testing.T) {
+	g := NewGomegaWithT(t)
+	m := NewMockTerminal(80, 24)
+	s, err := NewScreenFromTerminal(m)
+	g.Expect(err).To(BeNil())
+	defer s.Close()
+
+	m.SendInput("\033_Gi=2147483647;OK\033\\")
+	g.Expect(s.SupportsKittyGraphics()).To(BeTrue())
+	g.Expect(s.SupportsKittyGraphics()).To(BeFalse(), "Expected no support when terminal doesn't reply")
+}
+
+func TestMatchKittyReply(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	match := matchKittyReply(1)
+	start, end := match([]byte("\033_Gi=12;OK\033\\\033_Gi=1;OK\033\\"))
+	g.Expect(start).To(Equal(12))
+	g.Expect(end).To(Equal(23))
+	start, _ = match([]byte("\033_Gi=1;OK"))
+	g.Expect(start).To(Equal(-1), "Expected incomplete reply not to match")
+}
@@ -0,0 +1,177 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KittyKeyboardFlags select the enhancements of the kitty keyboard protocol
+type KittyKeyboardFlags int
+
+const (
+	// KittyDisambiguate reports the keys that are ambiguous in legacy encoding, like Esc, Alt combinations and
+	// Ctrl+I, as unique sequences
+	KittyDisambiguate KittyKeyboardFlags = 1 << iota
+	// KittyReportEventTypes reports key repeats and releases
+	KittyReportEventTypes
+	// KittyReportAlternateKeys reports the shifted key and the key in the standard layout
+	KittyReportAlternateKeys
+	// KittyReportAllKeys reports all the keys, including Enter, Tab and Backspace, as escape sequences
+	KittyReportAllKeys
+	// KittyReportText reports the text produced by the keys, it requires KittyReportAllKeys
+	KittyReportText
+)
+
+const kittyKeyboardQueryTimeout = 500 * time.Millisecond
+
+// kittyFunctionalKeys are the keys reported with their own codes in "CSI code u" sequences
+var kittyFunctionalKeys = map[int]Key{
+	27: KeyEscape, 13: KeyEnter, 9: KeyTab, 127: KeyBackspace,
+	57414: KeyEnter, 57417: KeyLeft, 57418: KeyRight, 57419: KeyUp, 57420: KeyDown, 57421: KeyPageUp,
+	57422: KeyPageDown, 57423: KeyHome, 57424: KeyEnd, 57425: KeyInsert, 57426: KeyDelete,
+}
+
+// kittyKeypadRunes are the characters of the keypad keys
+var kittyKeypadRunes = map[int]rune{
+	57409: '.', 57410: '/', 57411: '*', 57412: '-', 57413: '+', 57415: '=', 57416: ',',
+}
+
+const (
+	kittyKeyF13       = 57376
+	kittyKeyKeypad0   = 57399
+	kittyPrivateStart = 0xE000
+	kittyPrivateEnd   = 0xF8FF
+)
+
+// EnableKittyKeyboard enables kitty keyboard protocol with the flags, if the terminal supports it. It returns
+// false if the terminal doesn't support the protocol, the keys are then decoded from the legacy encoding.
+// The previous flags are restored when the screen is closed.
+func (s *Screen) EnableKittyKeyboard(flags KittyKeyboardFlags) (bool, error) {
+	// terminals that don't support the protocol ignore the query, but reply to the following primary device
+	// attributes request, so there is no need to wait for the timeout
+	// the device attributes reply follows the flags reply and must be kept until it is read
+	defer s.input.expect(matchPrivateReply("c"))()
+	reply, err := s.input.query(func() {
+		s.writeEsc("?u")
+		s.writeEsc("c")
+	}, matchPrivateReply("uc"), kittyKeyboardQueryTimeout)
+	if errors.Is(err, ErrTimeout) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if bytes.HasSuffix(reply, []byte("c")) {
+		return false, nil
+	}
+	// device attributes reply follows, remove it from the input
+	if _, err = s.input.readReply(matchPrivateReply("c"), kittyKeyboardQueryTimeout); err != nil {
+		return false, err
+	}
+	s.PushKittyKeyboard(flags)
+	return true, nil
+}
+
+// PushKittyKeyboard pushes the flags onto the terminal's stack of keyboard modes, making them active.
+// All the pushed flags are popped when the screen is closed
+func (s *Screen) PushKittyKeyboard(flags KittyKeyboardFlags) {
+	s.writeEsc(fmt.Sprintf(">%du", flags))
+	s.kittyKeyboardPushes++
+}
+
+// PopKittyKeyboard pops n entries from the terminal's stack of keyboard modes, restoring the previous flags
+func (s *Screen) PopKittyKeyboard(n int) {
+	n = min(n, s.kittyKeyboardPushes)
+	if n <= 0 {
+		return
+	}
+	s.writeEsc(fmt.Sprintf("<%du", n))
+	s.kittyKeyboardPushes -= n
+}
+
+// QueryKittyKeyboard returns the active kitty keyboard protocol flags. It returns ScreenError with ErrTimeout
+// cause if the terminal doesn't support the protocol
+func (s *Screen) QueryKittyKeyboard() (KittyKeyboardFlags, error) {
+	reply, err := s.input.query(func() { s.writeEsc("?u") }, matchPrivateReply("u"), kittyKeyboardQueryTimeout)
+	if err != nil {
+		return 0, err
+	}
+	flags, _ := strconv.Atoi(string(reply[len(esc)+1 : len(reply)-1]))
+	return KittyKeyboardFlags(flags), nil
+}
+
+// matchPrivateReply matches "CSI ? params final" reply with one of the final characters, like
+// "CSI ? flags u" reply to kitty keyboard query or "CSI ? attributes c" reply to device attributes request
+func matchPrivateReply(finals string) replyMatcher {
+	return func(buf []byte) (int, int) {
+		for start := 0; start < len(buf); start++ {
+			i := bytes.Index(buf[start:], []byte(esc+"?"))
+			if i < 0 {
+				return -1, -1
+			}
+			start += i
+			end := start + len(esc) + 1
+			for end < len(buf) && (buf[end] >= '0' && buf[end] <= '9' || buf[end] == ';') {
+				end++
+			}
+			if end < len(buf) && strings.IndexByte(finals, buf[end]) >= 0 {
+				return start, end + 1
+			}
+		}
+		return -1, -1
+	}
+}
+
+// decodeKittyKey decodes "CSI code:shifted:base ; modifiers:event ; text u" sequence.
+// It returns nil for the keys that can't be represented by KeyEvent, like the modifier keys
+func decodeKittyKey(params string) Event {
+	parts := strings.Split(params, ";")
+	codes := strings.Split(parts[0], ":")
+	code, err := strconv.Atoi(codes[0])
+	if err != nil {
+		return nil
+	}
+	ev := KeyEvent{}
+	ev.Mod, ev.Action = modifierParam(parts)
+	if len(codes) > 1 {
+		shifted, _ := strconv.Atoi(codes[1])
+		ev.ShiftedRune = rune(shifted)
+	}
+	if len(codes) > 2 {
+		base, _ := strconv.Atoi(codes[2])
+		ev.BaseRune = rune(base)
+	}
+	if len(parts) > 2 {
+		var text strings.Builder
+		for _, c := range strings.Split(parts[2], ":") {
+			if r, err := strconv.Atoi(c); err == nil {
+				text.WriteRune(rune(r))
+			}
+		}
+		ev.Text = text.String()
+	}
+	switch {
+	case kittyFunctionalKeys[code] != KeyRune:
+		ev.Key = kittyFunctionalKeys[code]
+	case code >= kittyKeyF13 && code <= kittyKeyF13+int(KeyF24-KeyF13):
+		ev.Key = KeyF13 + Key(code-kittyKeyF13)
+	case code >= kittyKeyKeypad0 && code <= kittyKeyKeypad0+9:
+		ev.Rune = rune('0' + code - kittyKeyKeypad0)
+	case kittyKeypadRunes[code] != 0:
+		ev.Rune = kittyKeypadRunes[code]
+	case code >= kittyPrivateStart && code <= kittyPrivateEnd:
+		return nil
+	case ev.Mod&ModShift != 0 && ev.ShiftedRune != 0:
+		ev.Rune = ev.ShiftedRune
+		ev.Mod &^= ModShift
+	default:
+		ev.Rune = rune(code)
+	}
+	return ev
+}
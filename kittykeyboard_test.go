@@ -0,0 +1,89 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDecodeEvent_KittyKeys(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := map[string]KeyEvent{
+		"\033[105;5u":        {Rune: 'i', Mod: ModCtrl},
+		"\033[9;5u":          {Key: KeyTab, Mod: ModCtrl},
+		"\033[27u":           {Key: KeyEscape},
+		"\033[97;1:3u":       {Rune: 'a', Action: KeyRelease},
+		"\033[97;1:2u":       {Rune: 'a', Action: KeyRepeat},
+		"\033[97:65;2u":      {Rune: 'A', ShiftedRune: 'A'},
+		"\033[1092::97;5u":   {Rune: 'ф', BaseRune: 'a', Mod: ModCtrl},
+		"\033[97;1;97u":      {Rune: 'a', Text: "a"},
+		"\033[57376;9u":      {Key: KeyF13, Mod: ModMeta},
+		"\033[57399u":        {Rune: '0'},
+		"\033[57414;33u":     {Key: KeyEnter, Mod: ModMeta},
+		"\033[1;5:3A":        {Key: KeyUp, Mod: ModCtrl, Action: KeyRelease},
+		"\033[3;1:2~":        {Key: KeyDelete, Action: KeyRepeat},
+		"\033[13~":           {Key: KeyF3},
+		"\033[97;65u":        {Rune: 'a', Mod: ModCapsLock},
+		"\033[120;3:1;120u":  {Rune: 'x', Mod: ModAlt, Text: "x"},
+		"\033[127;1:3u":      {Key: KeyBackspace, Action: KeyRelease},
+		"\033[57416;129u":    {Rune: ',', Mod: ModNumLock},
+		"\033[108;17u":       {Rune: 'l', Mod: ModHyper},
+		"\033[99:67;6:1;67u": {Rune: 'C', ShiftedRune: 'C', Mod: ModCtrl, Text: "C"},
+		"\033[1;2P":          {Key: KeyF1, Mod: ModShift},
+		"\033[1;1:1D":        {Key: KeyLeft},
+		"\033[97;1:1;97:98u": {Rune: 'a', Text: "ab"},
+		"\033[57387;1:1u":    {Key: KeyF24},
+		"\033[13;2u":         {Key: KeyEnter, Mod: ModShift},
+		"\033[32;5u":         {Rune: ' ', Mod: ModCtrl},
+		"\033[57417;5u":      {Key: KeyLeft, Mod: ModCtrl},
+		"\033[57409u":        {Rune: '.'},
+		"\033[1;1:3H":        {Key: KeyHome, Action: KeyRelease},
+	}
+	for input, expected := range cases {
+		ev, n := decodeEvent([]byte(input), false)
+		g.Expect(ev).To(Equal(expected), "Unexpected event for %q", input)
+		g.Expect(n).To(Equal(len(input)), "Expected the whole input %q to be decoded", input)
+	}
+
+	ev, _ := decodeEvent([]byte("\033[57441;2u"), false)
+	g.Expect(ev).To(BeNil(), "Expected modifier key to be skipped")
+}
+
+func TestScreen_EnableKittyKeyboard(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+
+	m.SendInput("x\033[?0u\033[?62;22c")
+	ok, err := s.EnableKittyKeyboard(KittyDisambiguate | KittyReportEventTypes)
+	g.Expect(err).To(BeNil())
+	g.Expect(ok).To(BeTrue())
+	g.Expect(m.Buffer.String()).To(Equal("\033[?u\033[c\033[>3u"))
+	ev, _ := s.ReadEvent(time.Second)
+	g.Expect(ev).To(Equal(KeyEvent{Rune: 'x'}), "Expected keys to be preserved")
+	g.Expect(s.input.pending).To(BeEmpty(), "Expected replies to be removed")
+
+	m.SendInput("\033[?3u")
+	flags, err := s.QueryKittyKeyboard()
+	g.Expect(err).To(BeNil())
+	g.Expect(flags).To(Equal(KittyDisambiguate | KittyReportEventTypes))
+
+	m.ResetBuffer()
+	s.Close()
+	g.Expect(m.Buffer.String()).To(HavePrefix("\033[<1u"), "Expected flags to be popped on close")
+}
+
+func TestScreen_EnableKittyKeyboard_Unsupported(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+	defer s.Close()
+
+	m.SendInput("\033[?62;22c")
+	ok, err := s.EnableKittyKeyboard(KittyDisambiguate)
+	g.Expect(err).To(BeNil())
+	g.Expect(ok).To(BeFalse())
+	g.Expect(m.Buffer.String()).To(Equal("\033[?u\033[c"), "Expected flags not to be pushed")
+}
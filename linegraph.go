@@ -0,0 +1,198 @@
+package ansie
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	defaultLineGraphHeight = 15
+	legendLineMarker       = '━'
+)
+
+// monochromeLineMarkers mark the points of the series when there are several series and no colours
+var monochromeLineMarkers = []rune("●○■□▲△◆◇")
+
+// LineSeries is a named series of values plotted by the LineGraph. Values are evenly spaced along the x-axis,
+// NaN and infinite values break the line.
+type LineSeries struct {
+	Name   string
+	Values []float64
+	Style  Style
+}
+
+// LineGraph renders line graphs with labelled axes and a legend. Lines are drawn on a braille Canvas, so the graph
+// has the resolution of 2x4 points per character cell. When colours are disabled and there are several series,
+// the points of each series are marked with a different character, which is also shown in the legend.
+type LineGraph struct {
+	Series []LineSeries
+	// Width and Height of the graph in cells including axes, labels and the legend. Default size is 60x15
+	Width  int
+	Height int
+	// Min and Max define the range of the y-axis. If both are zero, the range is calculated from the values
+	Min float64
+	Max float64
+	// XLabels are spread evenly along the x-axis, the first one at the start and the last one at the end of the axis
+	XLabels []string
+	// YFormat is fmt format of the y-axis labels, "%g" by default
+	YFormat string
+	// AxisStyle is the style of the axes and their labels
+	AxisStyle Style
+}
+
+// Render adds the graph to the AnsiBuffer's buffer. Every line except the last one is terminated with a line break.
+// The legend is added if any of the series has a name.
+func (lg *LineGraph) Render(a *AnsiBuffer) *AnsiBuffer {
+	width, height := lg.Width, lg.Height
+	if width <= 0 {
+		width = defaultChartWidth
+	}
+	if height <= 0 {
+		height = defaultLineGraphHeight
+	}
+	low, high := lg.Min, lg.Max
+	if low == 0 && high == 0 {
+		var all []float64
+		for _, s := range lg.Series {
+			all = append(all, s.Values...)
+		}
+		low, high = valueRange(all)
+	}
+	if high <= low {
+		high = low + 1
+	}
+	hasLegend := false
+	for _, s := range lg.Series {
+		hasLegend = hasLegend || s.Name != ""
+	}
+	rows := height - 1
+	if len(lg.XLabels) > 0 {
+		rows--
+	}
+	if hasLegend {
+		rows--
+	}
+	rows = max(rows, 1)
+	yLabels := map[int]string{rows - 1: formatValue(lg.YFormat, low)}
+	yLabels[0] = formatValue(lg.YFormat, high)
+	if rows > 2 {
+		// the middle value is labelled in the row containing its point
+		middle := int(math.Round(float64(rows*canvasCellHeight-1)/2)) / canvasCellHeight
+		yLabels[middle] = formatValue(lg.YFormat, (high+low)/2)
+	}
+	labelWidth := 0
+	for _, label := range yLabels {
+		labelWidth = max(labelWidth, StringWidth(label))
+	}
+	cols := max(width-labelWidth-1, 1)
+
+	canvas := NewCanvas(cols, rows)
+	for _, s := range lg.Series {
+		canvas.Pen = s.Style
+		lg.plot(canvas, s.Values, low, high)
+	}
+	if lg.markPoints(a) {
+		for i, s := range lg.Series {
+			canvas.Pen = s.Style
+			for k, v := range s.Values {
+				if isFinite(v) {
+					x, y := point(canvas, k, len(s.Values), v, low, high)
+					canvas.mark(x, y, lg.marker(a, i))
+				}
+			}
+		}
+	}
+	for row := 0; row < rows; row++ {
+		if label, found := yLabels[row]; found {
+			a.WithStyle(lg.AxisStyle, Align(label, labelWidth, AlignRight)+"┤")
+		} else {
+			a.WithStyle(lg.AxisStyle, strings.Repeat(" ", labelWidth)+"│")
+		}
+		canvas.renderRow(a, row)
+		a.CR()
+	}
+	a.WithStyle(lg.AxisStyle, strings.Repeat(" ", labelWidth)+"└"+strings.Repeat("─", cols))
+	if len(lg.XLabels) > 0 {
+		a.CR().A(strings.Repeat(" ", labelWidth+1))
+		renderCells(a, lg.xAxisLabels(cols))
+	}
+	if hasLegend {
+		a.CR()
+		names := make([]string, len(lg.Series))
+		styles := make([]Style, len(lg.Series))
+		for i, s := range lg.Series {
+			names[i] = s.Name
+			styles[i] = s.Style
+		}
+		renderLegend(a, names, styles, func(i int) rune { return lg.marker(a, i) })
+	}
+	return a
+}
+
+func (lg *LineGraph) plot(canvas *Canvas, values []float64, low, high float64) {
+	prevX, prevY := -1, -1
+	for i, v := range values {
+		if !isFinite(v) {
+			prevX = -1
+			continue
+		}
+		x, y := point(canvas, i, len(values), v, low, high)
+		if prevX < 0 {
+			canvas.Set(x, y)
+		} else {
+			canvas.Line(prevX, prevY, x, y)
+		}
+		prevX, prevY = x, y
+	}
+}
+
+// point returns the position of i-th of n values on the canvas
+func point(canvas *Canvas, i, n int, v, low, high float64) (int, int) {
+	x := 0
+	if n > 1 {
+		x = int(math.Round(float64(i) * float64(canvas.Width()-1) / float64(n-1)))
+	}
+	y := int(math.Round((high - clampF(v, low, high)) / (high - low) * float64(canvas.Height()-1)))
+	return x, y
+}
+
+// markPoints returns true if the points of the series must be marked, because the lines have the same colour
+func (lg *LineGraph) markPoints(a *AnsiBuffer) bool {
+	return len(lg.Series) > 1 && isMonochrome(a)
+}
+
+// marker returns the legend marker of the series
+func (lg *LineGraph) marker(a *AnsiBuffer, series int) rune {
+	if lg.markPoints(a) {
+		return monochromeLineMarkers[series%len(monochromeLineMarkers)]
+	}
+	return legendLineMarker
+}
+
+// xAxisLabels positions the labels along the axis. The first label is aligned to the left, the last one to the right
+// and the rest are centred on their positions. Labels that would overlap the previous label are skipped
+func (lg *LineGraph) xAxisLabels(cols int) []chartCell {
+	cells := blankCells(cols)
+	next := 0
+	for i, label := range lg.XLabels {
+		label = StripAnsi(label)
+		w := StringWidth(label)
+		pos := 0
+		switch {
+		case len(lg.XLabels) == 1:
+		case i == len(lg.XLabels)-1:
+			pos = cols - w
+		default:
+			pos = i*(cols-1)/(len(lg.XLabels)-1) - w/2
+			if i == 0 {
+				pos = 0
+			}
+		}
+		if pos < next || pos+w > cols {
+			continue
+		}
+		putText(cells, pos, label, lg.AxisStyle)
+		next = pos + w + 1
+	}
+	return cells
+}
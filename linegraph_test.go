@@ -0,0 +1,104 @@
+package ansie
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestLineGraph_Render(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	lg := &LineGraph{
+		Series:  []LineSeries{{Values: []float64{0, 10}}},
+		Width:   6,
+		Height:  4,
+		XLabels: []string{"a", "b"},
+	}
+	lines := strings.Split(lg.Render(a).String(), "\n")
+	g.Expect(lines).To(Equal([]string{
+		"10┤ ⢀⠎",
+		" 0┤⡰⠁ ",
+		"  └───",
+		"   a b",
+	}))
+}
+
+func TestLineGraph_Legend(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	lg := &LineGraph{
+		Series: []LineSeries{
+			{Name: "up", Values: []float64{0, 1}, Style: NewStyle().Fg(Green)},
+			{Name: "down", Values: []float64{1, 0}, Style: NewStyle().Fg(Red)},
+		},
+		Width:  20,
+		Height: 8,
+	}
+	lines := strings.Split(lg.Render(NewAnsi()).String(), "\n")
+	g.Expect(lines).To(HaveLen(8))
+	g.Expect(StringWidth(lines[1])).To(Equal(20))
+	g.Expect(lines[0]).To(HavePrefix("  1┤"))
+	g.Expect(lines[3]).To(HavePrefix("0.5┤"))
+	g.Expect(lines[5]).To(HavePrefix("  0┤"))
+	g.Expect(lines[6]).To(Equal("   └" + strings.Repeat("─", 16)))
+	g.Expect(lines[7]).To(Equal("\033[32m━\033[0m up  \033[31m━\033[0m down"))
+}
+
+func TestLineGraph_Gaps(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	lg := &LineGraph{
+		Series: []LineSeries{{Values: []float64{1, 1, math.NaN(), 1, 1}}},
+		Width:  6,
+		Height: 2,
+		Min:    0,
+		Max:    1,
+	}
+	g.Expect(strings.Split(lg.Render(a).String(), "\n")[0]).To(Equal("1┤⠉⠁⠈⠉"))
+}
+
+func TestLineGraph_Monochrome(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	lg := &LineGraph{
+		Series: []LineSeries{
+			{Name: "up", Values: []float64{0, 1}},
+			{Name: "down", Values: []float64{1, 0}},
+		},
+		Width:  6,
+		Height: 4,
+	}
+	lines := strings.Split(lg.Render(a).String(), "\n")
+	g.Expect(lines).To(Equal([]string{
+		"1┤○⢄⡠●",
+		"0┤●⠊⠑○",
+		" └────",
+		"● up  ○ down",
+	}), "Expected points of the series to be marked with different characters")
+}
+
+func TestLineGraph_Infinite(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetEnabled(false)
+	lg := &LineGraph{
+		Series: []LineSeries{{Values: []float64{1, 2, math.Inf(1)}}},
+		Width:  5,
+		Height: 3,
+	}
+	g.Expect(strings.Split(lg.Render(a).String(), "\n")).To(Equal([]string{
+		"2┤ ⡜ ",
+		"1┤⡜  ",
+		" └───",
+	}), "Expected infinite values to be skipped")
+}
@@ -5,11 +5,14 @@ package ansie
 import (
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/sys/unix"
 )
 
 type MockTerminal struct {
+	mu            sync.Mutex
 	FileDesc      int
 	Width         int
 	Height        int
@@ -18,6 +21,8 @@ type MockTerminal struct {
 	CursorVisible bool
 	State         unix.Termios
 	Buffer        strings.Builder
+	input         chan []byte
+	pendingInput  []byte
 }
 
 func NewMockTerminal(width, height int) *MockTerminal {
@@ -31,6 +36,7 @@ func NewMockTerminal(width, height int) *MockTerminal {
 		State: unix.Termios{
 			Lflag: unix.ECHO | unix.ICANON,
 		},
+		input: make(chan []byte, 64),
 	}
 }
 
@@ -57,11 +63,46 @@ func (m *MockTerminal) SetState(termState *unix.Termios) error {
 
 // Write implements Terminal.
 func (m *MockTerminal) Write(s string) (n int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return m.Buffer.WriteString(s)
 }
 
+// Output returns the text written to the terminal
+func (m *MockTerminal) Output() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Buffer.String()
+}
+
+// Read implements TerminalReader.
+func (m *MockTerminal) Read(p []byte, timeout time.Duration) (n int, err error) {
+	if len(m.pendingInput) == 0 {
+		var timer <-chan time.Time
+		if timeout >= 0 {
+			timer = time.After(timeout)
+		}
+		select {
+		case data := <-m.input:
+			m.pendingInput = data
+		case <-timer:
+			return 0, nil
+		}
+	}
+	n = copy(p, m.pendingInput)
+	m.pendingInput = m.pendingInput[n:]
+	return n, nil
+}
+
+// SendInput simulates the user typing or the terminal replying with the given data
+func (m *MockTerminal) SendInput(data string) {
+	m.input <- []byte(data)
+}
+
 // GetSize implements Terminal.
 func (m *MockTerminal) GetSize() (*unix.Winsize, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	return &unix.Winsize{
 		Row:    uint16(m.Height),
 		Col:    uint16(m.Width),
@@ -71,14 +112,18 @@ func (m *MockTerminal) GetSize() (*unix.Winsize, error) {
 }
 
 func (m *MockTerminal) SetSize(w, h int, c chan os.Signal) {
+	m.mu.Lock()
 	m.Width = w
 	m.Height = h
+	m.mu.Unlock()
 	if c != nil {
 		c <- os.Signal(unix.SIGWINCH) // Simulate a signal for testing purposes
 	}
 }
 
 func (m *MockTerminal) ResetBuffer() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Buffer.Reset()
 }
 
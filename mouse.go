@@ -0,0 +1,157 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MouseMode defines which mouse events are reported by the terminal
+type MouseMode int
+
+const (
+	// MouseOff disables mouse tracking
+	MouseOff MouseMode = 0
+	// MouseX10 reports button presses only
+	MouseX10 MouseMode = 9
+	// MouseNormal reports button presses, releases and the wheel
+	MouseNormal MouseMode = 1000
+	// MouseButtonEvent also reports the movement of the mouse while a button is pressed
+	MouseButtonEvent MouseMode = 1002
+	// MouseAnyMotion reports all the movements of the mouse
+	MouseAnyMotion MouseMode = 1003
+)
+
+const (
+	mouseEncodingUrxvt = 1015
+	mouseEncodingSgr   = 1006
+)
+
+// MouseButton identifies the mouse button or the direction of the wheel
+type MouseButton int
+
+const (
+	MouseNoButton MouseButton = iota
+	MouseLeft
+	MouseMiddle
+	MouseRight
+	MouseWheelUp
+	MouseWheelDown
+	MouseWheelLeft
+	MouseWheelRight
+	MouseButton8
+	MouseButton9
+	MouseButton10
+	MouseButton11
+)
+
+// MouseAction is the kind of the mouse event
+type MouseAction int
+
+const (
+	// MousePress is reported when a button is pressed or the wheel is turned
+	MousePress MouseAction = iota
+	// MouseRelease is reported when a button is released. Some encodings don't report which button was released,
+	// then Button is MouseNoButton
+	MouseRelease
+	// MouseMotion is reported when the mouse moves without any button pressed
+	MouseMotion
+	// MouseDrag is reported when the mouse moves with a button pressed
+	MouseDrag
+)
+
+// MouseEvent is a mouse button press or release, a wheel turn or a movement of the mouse
+type MouseEvent struct {
+	// X and Y are the 1-based coordinates of the cell under the mouse pointer
+	X, Y   int
+	Button MouseButton
+	Action MouseAction
+	// Mod contains the modifiers held during the event. Terminals report only Shift, Alt and Ctrl,
+	// and often use some combinations for their own purposes
+	Mod Modifier
+}
+
+func (MouseEvent) isEvent() {}
+
+// EnableMouse enables reporting of the mouse events, which are received with ReadEvent as MouseEvent.
+// The terminal is asked to use SGR or urxvt encoding, which support coordinates beyond 223.
+// MouseOff disables mouse tracking. Mouse tracking is disabled when the screen is closed.
+func (s *Screen) EnableMouse(mode MouseMode) {
+	if s.mouseMode != MouseOff {
+		s.writeEsc(fmt.Sprintf("?%d;%d;%dl", s.mouseMode, mouseEncodingUrxvt, mouseEncodingSgr))
+	}
+	if mode != MouseOff {
+		s.writeEsc(fmt.Sprintf("?%d;%d;%dh", mode, mouseEncodingUrxvt, mouseEncodingSgr))
+	}
+	s.mouseMode = mode
+}
+
+// DisableMouse disables reporting of the mouse events
+func (s *Screen) DisableMouse() {
+	s.EnableMouse(MouseOff)
+}
+
+// decodeMouseButtons decodes the button code of the mouse report. The low bits identify the button, higher bits
+// contain modifiers, motion and wheel flags
+func decodeMouseButtons(code int, x, y int, release bool) MouseEvent {
+	ev := MouseEvent{X: x, Y: y, Action: MousePress}
+	if code&4 != 0 {
+		ev.Mod |= ModShift
+	}
+	if code&8 != 0 {
+		ev.Mod |= ModAlt
+	}
+	if code&16 != 0 {
+		ev.Mod |= ModCtrl
+	}
+	button := code & 3
+	switch {
+	case code&128 != 0:
+		ev.Button = MouseButton8 + MouseButton(button)
+	case code&64 != 0:
+		ev.Button = MouseWheelUp + MouseButton(button)
+	case button == 3:
+		// legacy encodings don't report the released button
+		ev.Button = MouseNoButton
+		release = true
+	default:
+		ev.Button = MouseLeft + MouseButton(button)
+	}
+	switch {
+	case code&32 != 0 && ev.Button == MouseNoButton:
+		ev.Action = MouseMotion
+	case code&32 != 0:
+		ev.Action = MouseDrag
+	case release:
+		ev.Action = MouseRelease
+	}
+	return ev
+}
+
+// decodeX10Mouse decodes "CSI M Cb Cx Cy" report, where the values are encoded as single bytes offset by 32
+func decodeX10Mouse(report []byte) MouseEvent {
+	return decodeMouseButtons(int(report[0])-32, int(report[1])-32, int(report[2])-32, false)
+}
+
+// decodeMouseParams decodes "CSI < b;x;y M" report of SGR encoding and "CSI b;x;y M" report of urxvt encoding
+func decodeMouseParams(params string, final byte) (MouseEvent, bool) {
+	sgr := strings.HasPrefix(params, "<")
+	parts := strings.Split(strings.TrimPrefix(params, "<"), ";")
+	if len(parts) != 3 {
+		return MouseEvent{}, false
+	}
+	values := make([]int, 3)
+	for i, p := range parts {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return MouseEvent{}, false
+		}
+		values[i] = v
+	}
+	if !sgr {
+		values[0] -= 32
+	}
+	return decodeMouseButtons(values[0], values[1], values[2], final == 'm'), true
+}
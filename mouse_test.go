@@ -0,0 +1,58 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDecodeEvent_Mouse(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := map[string]MouseEvent{
+		"\033[<0;10;5M":   {X: 10, Y: 5, Button: MouseLeft, Action: MousePress},
+		"\033[<2;300;5m":  {X: 300, Y: 5, Button: MouseRight, Action: MouseRelease},
+		"\033[<32;1;2M":   {X: 1, Y: 2, Button: MouseLeft, Action: MouseDrag},
+		"\033[<35;1;2M":   {X: 1, Y: 2, Button: MouseNoButton, Action: MouseMotion},
+		"\033[<65;3;4M":   {X: 3, Y: 4, Button: MouseWheelDown, Action: MousePress},
+		"\033[<20;3;4M":   {X: 3, Y: 4, Button: MouseLeft, Action: MousePress, Mod: ModShift | ModCtrl},
+		"\033[<129;3;4M":  {X: 3, Y: 4, Button: MouseButton9, Action: MousePress},
+		"\033[33;20;10M":  {X: 20, Y: 10, Button: MouseMiddle, Action: MousePress},
+		"\033[35;20;10M":  {X: 20, Y: 10, Button: MouseNoButton, Action: MouseRelease},
+		"\033[M !\"":      {X: 1, Y: 2, Button: MouseLeft, Action: MousePress},
+		"\033[M(!\"":      {X: 1, Y: 2, Button: MouseLeft, Action: MousePress, Mod: ModAlt},
+		"\033[M`\x2a\x2b": {X: 10, Y: 11, Button: MouseWheelUp, Action: MousePress},
+	}
+	for input, expected := range cases {
+		ev, n := decodeEvent([]byte(input), false)
+		g.Expect(ev).To(Equal(expected), "Unexpected event for %q", input)
+		g.Expect(n).To(Equal(len(input)), "Expected the whole input %q to be decoded", input)
+	}
+
+	_, n := decodeEvent([]byte("\033[M !"), false)
+	g.Expect(n).To(Equal(0), "Expected incomplete X10 report")
+}
+
+func TestScreen_EnableMouse(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+
+	s.EnableMouse(MouseAnyMotion)
+	g.Expect(m.Buffer.String()).To(Equal("\033[?1003;1015;1006h"))
+
+	m.SendInput("\033[<0;10;5M")
+	ev, err := s.ReadEvent(time.Second)
+	g.Expect(err).To(BeNil())
+	g.Expect(ev).To(Equal(MouseEvent{X: 10, Y: 5, Button: MouseLeft, Action: MousePress}))
+
+	m.ResetBuffer()
+	s.EnableMouse(MouseX10)
+	g.Expect(m.Buffer.String()).To(Equal("\033[?1003;1015;1006l\033[?9;1015;1006h"))
+
+	m.ResetBuffer()
+	s.Close()
+	g.Expect(m.Buffer.String()).To(HavePrefix("\033[?9;1015;1006l"), "Expected mouse tracking to be disabled on close")
+}
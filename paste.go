@@ -0,0 +1,78 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"bytes"
+)
+
+const (
+	pasteEnd = "\033[201~"
+	// DefaultMaxPasteSize is the maximum size of the pasted text in bytes, the rest of the text is dropped
+	DefaultMaxPasteSize = 1 << 20
+)
+
+// PasteEvent is the text pasted into the terminal when bracketed paste is enabled
+type PasteEvent struct {
+	Text string
+	// Truncated is true if the pasted text was longer than the maximum paste size and was cut
+	Truncated bool
+}
+
+func (PasteEvent) isEvent() {}
+
+// pasteStart marks the beginning of the pasted text
+type pasteStart struct{}
+
+func (pasteStart) isEvent() {}
+
+// EnableBracketedPaste makes the terminal mark the pasted text, so that it is received with ReadEvent as
+// a single PasteEvent instead of the key presses. Line breaks in the pasted text are not reported as Enter.
+// Bracketed paste is disabled when the screen is closed.
+func (s *Screen) EnableBracketedPaste() {
+	s.writeEsc("?2004h")
+	s.bracketedPaste = true
+}
+
+// DisableBracketedPaste disables marking of the pasted text
+func (s *Screen) DisableBracketedPaste() {
+	if s.bracketedPaste {
+		s.writeEsc("?2004l")
+		s.bracketedPaste = false
+	}
+}
+
+// SetMaxPasteSize sets the maximum size of the pasted text in bytes. Defaults to DefaultMaxPasteSize
+func (s *Screen) SetMaxPasteSize(size int) {
+	s.input.mu.Lock()
+	defer s.input.mu.Unlock()
+	s.input.maxPasteSize = size
+}
+
+// readPaste moves the pasted text from the pending input starting at i to the paste buffer. It returns
+// the PasteEvent when the end of the pasted text is received
+func (r *inputReader) readPaste(i int) (Event, bool) {
+	end := bytes.Index(r.pending[i:], []byte(pasteEnd))
+	n := end
+	if end < 0 {
+		// keep the bytes that can be the beginning of the end marker
+		n = max(len(r.pending)-i-len(pasteEnd)+1, 0)
+	}
+	r.appendPaste(r.pending[i : i+n])
+	if end < 0 {
+		r.pending = append(r.pending[:i], r.pending[i+n:]...)
+		return nil, false
+	}
+	r.pending = append(r.pending[:i], r.pending[i+n+len(pasteEnd):]...)
+	ev := PasteEvent{Text: string(r.paste), Truncated: r.pasteTruncated}
+	r.paste, r.pasting, r.pasteTruncated = nil, false, false
+	return ev, true
+}
+
+func (r *inputReader) appendPaste(text []byte) {
+	if room := r.maxPasteSize - len(r.paste); len(text) > room {
+		text = text[:max(room, 0)]
+		r.pasteTruncated = true
+	}
+	r.paste = append(r.paste, text...)
+}
@@ -0,0 +1,41 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestScreen_BracketedPaste(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 80, 24)
+
+	s.EnableBracketedPaste()
+	g.Expect(m.Buffer.String()).To(Equal("\033[?2004h"))
+
+	m.SendInput("a\033[200~line 1\r\nline 2\033[20")
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		m.SendInput("1~b")
+	}()
+	ev, err := s.ReadEvent(time.Second)
+	g.Expect(err).To(BeNil())
+	g.Expect(ev).To(Equal(KeyEvent{Rune: 'a'}))
+	ev, _ = s.ReadEvent(time.Second)
+	g.Expect(ev).To(Equal(PasteEvent{Text: "line 1\r\nline 2"}), "Expected paste split between reads to be joined")
+	ev, _ = s.ReadEvent(time.Second)
+	g.Expect(ev).To(Equal(KeyEvent{Rune: 'b'}))
+
+	s.SetMaxPasteSize(5)
+	m.SendInput("\033[200~" + strings.Repeat("x", 10) + "\033[201~")
+	ev, _ = s.ReadEvent(time.Second)
+	g.Expect(ev).To(Equal(PasteEvent{Text: "xxxxx", Truncated: true}))
+
+	m.ResetBuffer()
+	s.Close()
+	g.Expect(m.Buffer.String()).To(HavePrefix("\033[?2004l"), "Expected bracketed paste to be disabled on close")
+}
@@ -0,0 +1,124 @@
+package ansie
+
+import (
+	"os"
+	"strings"
+)
+
+// ColourProfile defines the set of colours the terminal is able to display.
+// AnsiBuffer converts the colours to the closest ones supported by its profile.
+type ColourProfile int
+
+const (
+	// NoColour profile doesn't output any colours, but text attributes are still emitted
+	NoColour ColourProfile = iota
+	// Colours16 profile supports 8 basic colours and their high-intensity versions
+	Colours16
+	// Colours256 profile supports the 256-colour palette
+	Colours256
+	// TrueColour profile supports 24-bit RGB colours
+	TrueColour
+)
+
+func (p ColourProfile) String() string {
+	switch p {
+	case NoColour:
+		return "no colour"
+	case Colours16:
+		return "16 colours"
+	case Colours256:
+		return "256 colours"
+	case TrueColour:
+		return "true colour"
+	default:
+		return "unknown"
+	}
+}
+
+// DetectColourProfile guesses the colour profile of the terminal from the environment variables.
+// It honours NO_COLOR convention (https://no-color.org) and checks COLORTERM and TERM variables.
+func DetectColourProfile() ColourProfile {
+	if _, found := os.LookupEnv("NO_COLOR"); found {
+		return NoColour
+	}
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return TrueColour
+	}
+	term := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case term == "dumb":
+		return NoColour
+	case strings.Contains(term, "truecolor") || strings.Contains(term, "direct"):
+		return TrueColour
+	case strings.Contains(term, "256color"):
+		return Colours256
+	default:
+		return Colours16
+	}
+}
+
+// Profile returns the colour profile of the AnsiBuffer
+func (ap *AnsiBuffer) Profile() ColourProfile {
+	return ap.profile
+}
+
+// SetProfile sets the colour profile of the AnsiBuffer. Colours added to the buffer afterwards are converted to
+// the closest colours supported by the profile. This does not affect the string already in AnsiBuffer's buffer
+func (ap *AnsiBuffer) SetProfile(profile ColourProfile) {
+	ap.profile = profile
+}
+
+// Rgb16 finds the closest colour in 16-colour palette for 24-bit RGB colour represented as 3 values
+func Rgb16(r uint, g uint, b uint) Colour {
+	return nearestPaletteColour(float64(clip(r, 255)), float64(clip(g, 255)), float64(clip(b, 255)), 0, 16)
+}
+
+// xterm default values for the 16 basic colours
+var basicPalette = [16][3]uint{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+var cubeLevels = [6]uint{0, 95, 135, 175, 215, 255}
+
+// paletteRgb returns RGB components of the colour from the 256-colour palette
+func paletteRgb(colour Colour) (r, g, b uint) {
+	switch {
+	case colour < 16:
+		c := basicPalette[max(colour, 0)]
+		return c[0], c[1], c[2]
+	case colour < 232:
+		colour -= 16
+		return cubeLevels[colour/36], cubeLevels[(colour/6)%6], cubeLevels[colour%6]
+	default:
+		gray := uint(8 + 10*(min(colour, 255)-232))
+		return gray, gray, gray
+	}
+}
+
+// nearestPaletteColour finds the colour closest to (r, g, b) among the palette colours in the range [first..last)
+func nearestPaletteColour(r, g, b float64, first, last Colour) Colour {
+	best := first
+	bestDistance := -1.0
+	for c := first; c < last; c++ {
+		pr, pg, pb := paletteRgb(c)
+		d := colourDistance(r, g, b, float64(pr), float64(pg), float64(pb))
+		if bestDistance < 0 || d < bestDistance {
+			best = c
+			bestDistance = d
+		}
+	}
+	return best
+}
+
+// colourDistance calculates perceptual distance between two colours using "redmean" approximation
+func colourDistance(r1, g1, b1, r2, g2, b2 float64) float64 {
+	rMean := (r1 + r2) / 2
+	dr := r1 - r2
+	dg := g1 - g2
+	db := b1 - b2
+	return (2+rMean/256)*dr*dr + 4*dg*dg + (2+(255-rMean)/256)*db*db
+}
@@ -0,0 +1,91 @@
+package ansie
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAnsiBuffer_Profile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	g.Expect(a.Profile()).To(Equal(TrueColour))
+	a.SetProfile(Colours16)
+	g.Expect(a.Profile()).To(Equal(Colours16))
+}
+
+func TestAnsiBuffer_Colours16Profile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetProfile(Colours16)
+	g.Expect(a.Fg(Red).A("text").String()).To(Equal("\033[31mtext"))
+	g.Expect(a.Fg(BrightYellow).A("text").String()).To(Equal("\033[93mtext"))
+	g.Expect(a.Bg(Red1).A("text").String()).To(Equal("\033[101mtext"))
+	g.Expect(a.FgRgb(0, 0, 230).A("text").String()).To(Equal("\033[34mtext"))
+	g.Expect(a.BgRgbI(0xFFFFFF).A("text").String()).To(Equal("\033[107mtext"))
+	g.Expect(a.FgGray(0).A("text").String()).To(Equal("\033[30mtext"))
+}
+
+func TestAnsiBuffer_Colours256Profile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetProfile(Colours256)
+	a.ColorCompatibility = true
+	g.Expect(a.Fg(Red).A("text").String()).To(Equal("\033[31mtext"))
+	g.Expect(a.FgRgb(255, 0, 0).A("text").String()).To(Equal("\033[38;5;196mtext"))
+	g.Expect(a.BgRgbI(0xFFFF00).A("text").String()).To(Equal("\033[48;5;226mtext"))
+}
+
+func TestAnsiBuffer_NoColourProfile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SetProfile(NoColour)
+	s := a.Fg(Red).FgHi(Blue).BgHi(Green).Bg(DarkGoldenrod).FgRgb(1, 2, 3).Attr(Bold).A("text").Reset().String()
+	g.Expect(s).To(Equal("\033[1mtext\033[0m"))
+}
+
+func TestRgb16(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Rgb16(0, 0, 0)).To(Equal(Black))
+	g.Expect(Rgb16(255, 255, 255)).To(Equal(BrightWhite))
+	g.Expect(Rgb16(200, 10, 10)).To(Equal(Red))
+	g.Expect(Rgb16(130, 130, 130)).To(Equal(Grey))
+}
+
+func TestPaletteRgb(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	r, gr, b := paletteRgb(Red1)
+	g.Expect([]uint{r, gr, b}).To(Equal([]uint{255, 0, 0}))
+	r, gr, b = paletteRgb(Grey3)
+	g.Expect([]uint{r, gr, b}).To(Equal([]uint{8, 8, 8}))
+	r, gr, b = paletteRgb(Grey93)
+	g.Expect([]uint{r, gr, b}).To(Equal([]uint{238, 238, 238}))
+}
+
+func TestDetectColourProfile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	t.Setenv("COLORTERM", "truecolor")
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("NO_COLOR", "")
+	g.Expect(DetectColourProfile()).To(Equal(NoColour))
+
+	_ = os.Unsetenv("NO_COLOR") // t.Setenv restores the original value after the test
+	g.Expect(DetectColourProfile()).To(Equal(TrueColour))
+
+	t.Setenv("COLORTERM", "")
+	g.Expect(DetectColourProfile()).To(Equal(Colours256))
+
+	t.Setenv("TERM", "xterm")
+	g.Expect(DetectColourProfile()).To(Equal(Colours16))
+
+	t.Setenv("TERM", "dumb")
+	g.Expect(DetectColourProfile()).To(Equal(NoColour))
+}
@@ -0,0 +1,418 @@
+package ansie
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultProgressBarWidth = 40
+	defaultProgressInterval = 100 * time.Millisecond
+	defaultPlainInterval    = 5 * time.Second
+	// width of the moving segment of indeterminate bars as a fraction of the bar width
+	indeterminateSegment = 4
+)
+
+// BarState is a snapshot of the progress bar state passed to decorators
+type BarState struct {
+	Name    string
+	Current int64
+	// Total is zero or negative for indeterminate bars
+	Total   int64
+	Elapsed time.Duration
+	Done    bool
+}
+
+// Rate returns the average progress per second since the bar was created
+func (s BarState) Rate() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Current) / s.Elapsed.Seconds()
+}
+
+// Fraction returns the completed part of the bar from 0 to 1. Indeterminate bars return 0 until they are done
+func (s BarState) Fraction() float64 {
+	if s.Total <= 0 {
+		if s.Done {
+			return 1
+		}
+		return 0
+	}
+	return clampF(float64(s.Current)/float64(s.Total), 0, 1)
+}
+
+// BarDecorator renders a part of the progress bar line from the bar state, such as percentage or ETA
+type BarDecorator func(s BarState) string
+
+// Percentage shows the completed percentage of determinate bars
+func Percentage() BarDecorator {
+	return func(s BarState) string {
+		if s.Total <= 0 {
+			return ""
+		}
+		return fmt.Sprintf("%3d%%", int(s.Fraction()*100))
+	}
+}
+
+// ETA shows the estimated time to completion of determinate bars based on the average rate
+func ETA() BarDecorator {
+	return func(s BarState) string {
+		switch {
+		case s.Total <= 0:
+			return ""
+		case s.Done || s.Current >= s.Total:
+			return "ETA 0s"
+		case s.Rate() <= 0:
+			return "ETA --"
+		}
+		remaining := time.Duration(float64(s.Total-s.Current) / s.Rate() * float64(time.Second))
+		return "ETA " + remaining.Round(time.Second).String()
+	}
+}
+
+// Elapsed shows the time since the bar was created
+func Elapsed() BarDecorator {
+	return func(s BarState) string {
+		return s.Elapsed.Round(time.Second).String()
+	}
+}
+
+// Rate shows the average progress per second, followed by the unit, for example "12.5 files/s"
+func Rate(unit string) BarDecorator {
+	return func(s BarState) string {
+		return strings.TrimSpace(fmt.Sprintf("%.1f %s", s.Rate(), unit)) + "/s"
+	}
+}
+
+// Counter shows the current and total values, for example "12/100"
+func Counter() BarDecorator {
+	return func(s BarState) string {
+		if s.Total <= 0 {
+			return fmt.Sprintf("%d", s.Current)
+		}
+		return fmt.Sprintf("%d/%d", s.Current, s.Total)
+	}
+}
+
+// Bytes shows the current and total values as sizes in bytes, for example "1.5 MiB / 10.0 MiB"
+func Bytes() BarDecorator {
+	return func(s BarState) string {
+		if s.Total <= 0 {
+			return formatBytes(float64(s.Current))
+		}
+		return formatBytes(float64(s.Current)) + " / " + formatBytes(float64(s.Total))
+	}
+}
+
+// BytesRate shows the average progress per second as a size in bytes, for example "2.3 MiB/s"
+func BytesRate() BarDecorator {
+	return func(s BarState) string {
+		return formatBytes(s.Rate()) + "/s"
+	}
+}
+
+// BarOptions define the appearance of a progress bar
+type BarOptions struct {
+	// Width of the bar itself in cells, without the name and decorators. Defaults to 40
+	Width int
+	// Style of the filled part of the bar
+	Style Style
+	// EmptyStyle of the unfilled part. Its background colour is also used for the partially filled cell
+	EmptyStyle Style
+	// Decorators are shown after the bar. Defaults to Percentage and ETA for determinate bars and Elapsed for
+	// indeterminate ones
+	Decorators []BarDecorator
+}
+
+// Bar is a single progress bar in the Progress container. All its methods are safe for concurrent use.
+type Bar struct {
+	progress *Progress
+	name     string
+	current  int64
+	total    int64
+	started  time.Time
+	finished time.Time
+	done     bool
+	reported bool
+	opts     BarOptions
+}
+
+// Add increases the current value of the bar by n
+func (b *Bar) Add(n int64) {
+	b.progress.mu.Lock()
+	defer b.progress.mu.Unlock()
+	b.current += n
+}
+
+// Increment increases the current value of the bar by one
+func (b *Bar) Increment() {
+	b.Add(1)
+}
+
+// SetCurrent sets the current value of the bar
+func (b *Bar) SetCurrent(current int64) {
+	b.progress.mu.Lock()
+	defer b.progress.mu.Unlock()
+	b.current = current
+}
+
+// SetTotal changes the total value of the bar. Setting the total of an indeterminate bar makes it determinate
+func (b *Bar) SetTotal(total int64) {
+	b.progress.mu.Lock()
+	defer b.progress.mu.Unlock()
+	b.total = total
+}
+
+// Done marks the bar as complete. Determinate bars are filled up to their total
+func (b *Bar) Done() {
+	b.progress.mu.Lock()
+	defer b.progress.mu.Unlock()
+	if !b.done {
+		b.done = true
+		b.finished = b.progress.now()
+		if b.total > 0 {
+			b.current = b.total
+		}
+	}
+}
+
+// State returns a snapshot of the bar state
+func (b *Bar) State() BarState {
+	b.progress.mu.Lock()
+	defer b.progress.mu.Unlock()
+	return b.state()
+}
+
+func (b *Bar) state() BarState {
+	end := b.progress.now()
+	if b.done {
+		end = b.finished
+	}
+	return BarState{
+		Name:    b.name,
+		Current: b.current,
+		Total:   b.total,
+		Elapsed: end.Sub(b.started),
+		Done:    b.done,
+	}
+}
+
+// Progress is a container of progress bars, which are redrawn in place on the terminal.
+// Bars can be updated from different goroutines.
+//
+// When the output is not a terminal, the state of the bars is printed as plain text lines every PlainInterval
+// and when a bar is done.
+type Progress struct {
+	// Interval between redraws on the terminal. Defaults to 100ms, which is also used if Interval is not positive
+	Interval time.Duration
+	// PlainInterval between status lines when the output is not a terminal. Defaults to 5s
+	PlainInterval time.Duration
+
+	mu        sync.Mutex
+	out       io.Writer
+	ansi      *AnsiBuffer
+	bars      []*Bar
+	drawn     int
+	frame     int
+	lastPlain time.Time
+	stop      chan struct{}
+	stopped   chan struct{}
+	now       func() time.Time
+}
+
+// NewProgress creates a progress bar container writing to w. Bars are drawn in place if w is a terminal,
+// otherwise plain text is written.
+func NewProgress(w io.Writer) *Progress {
+	ansi := NewAnsi()
+	if f, ok := w.(*os.File); ok {
+		ansi = NewAnsiFor(f)
+	} else {
+		ansi.SetEnabled(false)
+	}
+	return &Progress{
+		Interval:      defaultProgressInterval,
+		PlainInterval: defaultPlainInterval,
+		out:           w,
+		ansi:          ansi,
+		now:           time.Now,
+	}
+}
+
+// AddBar adds a new bar to the container. Total of zero or less creates an indeterminate bar.
+// If opts is nil, the default options are used
+func (p *Progress) AddBar(name string, total int64, opts *BarOptions) *Bar {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	bar := &Bar{progress: p, name: name, total: total, started: p.now()}
+	if opts != nil {
+		bar.opts = *opts
+	}
+	if bar.opts.Width <= 0 {
+		bar.opts.Width = defaultProgressBarWidth
+	}
+	if bar.opts.EmptyStyle.IsDefault() {
+		bar.opts.EmptyStyle = NewStyle().BgHi(Black)
+	}
+	if bar.opts.Decorators == nil {
+		if total > 0 {
+			bar.opts.Decorators = []BarDecorator{Percentage(), ETA()}
+		} else {
+			bar.opts.Decorators = []BarDecorator{Elapsed()}
+		}
+	}
+	p.bars = append(p.bars, bar)
+	return bar
+}
+
+// Start starts redrawing the bars in the background until Stop is called
+func (p *Progress) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stop != nil {
+		return
+	}
+	p.stop = make(chan struct{})
+	p.stopped = make(chan struct{})
+	go p.run(p.stop, p.stopped)
+}
+
+// Stop stops redrawing the bars and draws their final state
+func (p *Progress) Stop() {
+	p.mu.Lock()
+	stop, stopped := p.stop, p.stopped
+	p.stop = nil
+	p.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-stopped
+	}
+	p.refresh(true)
+}
+
+// Refresh redraws the bars immediately
+func (p *Progress) Refresh() {
+	p.refresh(false)
+}
+
+func (p *Progress) run(stop chan struct{}, stopped chan struct{}) {
+	defer close(stopped)
+	interval := p.Interval
+	if interval <= 0 {
+		interval = defaultProgressInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			p.refresh(false)
+		}
+	}
+}
+
+func (p *Progress) refresh(final bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.frame++
+	nameWidth := 0
+	for _, bar := range p.bars {
+		nameWidth = max(nameWidth, StringWidth(bar.name))
+	}
+	a := p.ansi
+	if a.IsEnabled() {
+		if p.drawn > 0 {
+			a.CursorUp(p.drawn)
+		}
+		for _, bar := range p.bars {
+			a.ClearLine().LF()
+			p.renderBar(a, bar, nameWidth)
+			a.CR()
+		}
+		p.drawn = len(p.bars)
+	} else {
+		now := p.now()
+		periodic := final || now.Sub(p.lastPlain) >= p.PlainInterval
+		if periodic {
+			p.lastPlain = now
+		}
+		for _, bar := range p.bars {
+			if bar.reported || !(periodic || bar.done) {
+				continue
+			}
+			bar.reported = bar.done
+			a.A(Align(bar.name, nameWidth, AlignLeft))
+			p.renderDecorators(a, bar)
+			a.CR()
+		}
+	}
+	_, _ = io.WriteString(p.out, a.String())
+}
+
+func (p *Progress) renderBar(a *AnsiBuffer, bar *Bar, nameWidth int) {
+	if nameWidth > 0 {
+		a.A(Align(bar.name, nameWidth, AlignLeft)).A(" ")
+	}
+	s := bar.state()
+	width := bar.opts.Width
+	cells := make([]chartCell, width)
+	for i := range cells {
+		cells[i] = chartCell{r: ' ', style: bar.opts.EmptyStyle}
+	}
+	partial := bar.opts.Style
+	partial.bg = bar.opts.EmptyStyle.bg
+	if s.Total <= 0 && !s.Done {
+		// indeterminate bar shows a segment bouncing between the ends of the bar
+		segment := max(width/indeterminateSegment, 1)
+		span := max(width-segment, 1)
+		pos := p.frame % (2 * span)
+		if pos > span {
+			pos = 2*span - pos
+		}
+		for i := pos; i < min(pos+segment, width); i++ {
+			cells[i] = chartCell{r: horizontalEighths[len(horizontalEighths)-1], style: bar.opts.Style}
+		}
+	} else {
+		eighths := int(math.Round(s.Fraction() * float64(width*8)))
+		for i := range cells {
+			switch {
+			case eighths >= (i+1)*8:
+				cells[i] = chartCell{r: horizontalEighths[len(horizontalEighths)-1], style: bar.opts.Style}
+			case eighths > i*8:
+				cells[i] = chartCell{r: horizontalEighths[eighths-i*8-1], style: partial}
+			}
+		}
+	}
+	renderCells(a, cells)
+	p.renderDecorators(a, bar)
+}
+
+func (p *Progress) renderDecorators(a *AnsiBuffer, bar *Bar) {
+	s := bar.state()
+	for _, decorator := range bar.opts.Decorators {
+		if text := decorator(s); text != "" {
+			a.A(" ").A(text)
+		}
+	}
+}
+
+// formatBytes formats the size in bytes using binary prefixes
+func formatBytes(size float64) string {
+	const units = "KMGTPE"
+	if size < 1024 {
+		return fmt.Sprintf("%.0f B", size)
+	}
+	unit := -1
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f %ciB", size, units[unit])
+}
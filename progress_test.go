@@ -0,0 +1,145 @@
+package ansie
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func newTestProgress(terminal bool) (*Progress, *bytes.Buffer, *fakeClock) {
+	out := &bytes.Buffer{}
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	p := NewProgress(out)
+	p.ansi.SetEnabled(terminal)
+	p.now = clock.now
+	return p, out, clock
+}
+
+func TestDecorators(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	s := BarState{Current: 25, Total: 100, Elapsed: 5 * time.Second}
+	g.Expect(Percentage()(s)).To(Equal(" 25%"))
+	g.Expect(ETA()(s)).To(Equal("ETA 15s"))
+	g.Expect(Rate("files")(s)).To(Equal("5.0 files/s"))
+	g.Expect(Counter()(s)).To(Equal("25/100"))
+	g.Expect(Elapsed()(s)).To(Equal("5s"))
+
+	s = BarState{Current: 3 << 20, Total: 10 << 20, Elapsed: 2 * time.Second}
+	g.Expect(Bytes()(s)).To(Equal("3.0 MiB / 10.0 MiB"))
+	g.Expect(BytesRate()(s)).To(Equal("1.5 MiB/s"))
+
+	s = BarState{Current: 10, Elapsed: time.Second}
+	g.Expect(Percentage()(s)).To(BeEmpty(), "Expected no percentage for indeterminate bar")
+	g.Expect(ETA()(s)).To(BeEmpty())
+	g.Expect(Counter()(s)).To(Equal("10"))
+}
+
+func TestProgress_Terminal(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p, out, clock := newTestProgress(true)
+	opts := &BarOptions{Width: 4, EmptyStyle: NewStyle().Bg(Blue), Decorators: []BarDecorator{Percentage()}}
+	bar := p.AddBar("a", 100, opts)
+	p.AddBar("long", 100, opts).Done()
+
+	bar.Add(30)
+	clock.t = clock.t.Add(time.Second)
+	p.Refresh()
+	g.Expect(out.String()).To(Equal(
+		"\033[2K\ra    █\033[44m▎  \033[0m  30%\n" +
+			"\033[2K\rlong ████ 100%\n"))
+
+	out.Reset()
+	bar.Done()
+	p.Refresh()
+	g.Expect(out.String()).To(HavePrefix("\033[2A\033[2K\ra    ████ 100%\n"), "Expected bars to be redrawn in place")
+}
+
+func TestProgress_Indeterminate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p, out, _ := newTestProgress(true)
+	p.AddBar("", 0, &BarOptions{Width: 8, EmptyStyle: NewStyle().Attr(Faint), Decorators: []BarDecorator{}})
+	p.Refresh()
+	g.Expect(StripAnsi(out.String())).To(Equal("\r ██     \n"))
+	out.Reset()
+	p.Refresh()
+	g.Expect(StripAnsi(out.String())).To(Equal("\r  ██    \n"))
+}
+
+func TestProgress_Plain(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p, out, clock := newTestProgress(false)
+	p.PlainInterval = time.Minute
+	a := p.AddBar("first", 10, &BarOptions{Decorators: []BarDecorator{Counter()}})
+	p.AddBar("second", 10, &BarOptions{Decorators: []BarDecorator{Counter()}})
+
+	p.Refresh()
+	g.Expect(out.String()).To(Equal("first  0/10\nsecond 0/10\n"))
+
+	out.Reset()
+	a.Done()
+	p.Refresh()
+	g.Expect(out.String()).To(Equal("first  10/10\n"), "Expected only the completed bar to be printed before the interval")
+
+	out.Reset()
+	clock.t = clock.t.Add(time.Minute)
+	p.Refresh()
+	g.Expect(out.String()).To(Equal("second 0/10\n"))
+
+	out.Reset()
+	p.Stop()
+	g.Expect(out.String()).To(Equal("second 0/10\n"), "Expected the final state of unfinished bars on Stop")
+}
+
+func TestProgress_Concurrent(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	p, _, _ := newTestProgress(true)
+	p.Interval = time.Millisecond
+	p.Start()
+	var wg sync.WaitGroup
+	bars := make([]*Bar, 4)
+	for i := range bars {
+		bars[i] = p.AddBar(strings.Repeat("x", i+1), 1000, nil)
+		wg.Add(1)
+		go func(b *Bar) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				b.Increment()
+			}
+		}(bars[i])
+	}
+	wg.Wait()
+	p.Stop()
+	for _, b := range bars {
+		g.Expect(b.State().Current).To(Equal(int64(1000)))
+	}
+}
+
+func TestProgress_NonPositiveInterval(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	out := &syncBuffer{}
+	p := NewProgress(out)
+	p.ansi.SetEnabled(true)
+	p.Interval = 0
+	p.AddBar("a", 10, nil)
+	p.Start()
+	g.Eventually(out.String).Should(ContainSubstring("a"), "Expected default interval to be used")
+	p.Stop()
+}
@@ -3,14 +3,29 @@
 package ansie
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"golang.org/x/sys/unix"
 )
 
+// resizeDebounce is the time to wait for more resize signals before the resize is handled
+const resizeDebounce = 30 * time.Millisecond
+
+// ErrTimeout is the cause of a ScreenError returned when the terminal doesn't reply to a query in time.
+var ErrTimeout = errors.New("timed out waiting for terminal")
+
+// ErrNoInput is the cause of a ScreenError returned when the input is needed, but the terminal doesn't implement
+// TerminalReader.
+var ErrNoInput = errors.New("terminal doesn't support reading input")
+
 type ScreenError struct {
 	Message string
 	Cause   error
@@ -30,6 +45,11 @@ func (err ScreenError) Error() string {
 	return fmt.Sprintf("screen error: %s, caused by: %v", err.Message, err.Cause)
 }
 
+// Unwrap returns the cause of the error, if any.
+func (err ScreenError) Unwrap() error {
+	return err.Cause
+}
+
 // Terminal interface defines abstract terminal operations.
 type Terminal interface {
 	// Fd returns the file descriptor of the terminal.
@@ -46,9 +66,19 @@ type Terminal interface {
 	GetSize() (*unix.Winsize, error) // Returns width and height of the terminal
 }
 
-// FileTerminal implements the Terminal interface backed by an os.File.
+// TerminalReader is implemented by the terminals that can read the input: key presses, mouse events and the
+// replies to the queries. Screen methods that need the input return ErrNoInput if the terminal doesn't implement it.
+type TerminalReader interface {
+	// Read reads input from the terminal, waiting at most timeout for the data to arrive.
+	// It returns zero bytes and no error if the timeout expires. Negative timeout waits indefinitely.
+	Read(p []byte, timeout time.Duration) (n int, err error)
+}
+
+// FileTerminal implements the Terminal interface backed by an os.File. The input is read from a separate file,
+// which is the same file unless the terminal is created with NewTerminalFromFiles.
 type FileTerminal struct {
-	file *os.File
+	file  *os.File
+	input *os.File
 }
 
 // GetSize implements Terminal.
@@ -57,12 +87,19 @@ func (t *FileTerminal) GetSize() (*unix.Winsize, error) {
 }
 
 var _ Terminal = (*FileTerminal)(nil)
+var _ TerminalReader = (*FileTerminal)(nil)
 
 func NewTerminalFromFile(file *os.File) (*FileTerminal, error) {
-	if file == nil {
+	return NewTerminalFromFiles(file, file)
+}
+
+// NewTerminalFromFiles creates the terminal that writes the output to output file and reads the input from
+// input file, like os.Stdout and os.Stdin.
+func NewTerminalFromFiles(input, output *os.File) (*FileTerminal, error) {
+	if input == nil || output == nil {
 		return nil, fmt.Errorf("file cannot be nil")
 	}
-	return &FileTerminal{file: file}, nil
+	return &FileTerminal{file: output, input: input}, nil
 }
 
 func (t *FileTerminal) Fd() int {
@@ -73,6 +110,28 @@ func (t *FileTerminal) Write(s string) (n int, err error) {
 	return t.file.WriteString(s)
 }
 
+func (t *FileTerminal) Read(p []byte, timeout time.Duration) (n int, err error) {
+	fd := int(t.input.Fd())
+	var fds unix.FdSet
+	fds.Set(fd)
+	var tv *unix.Timeval
+	if timeout >= 0 {
+		timeval := unix.NsecToTimeval(timeout.Nanoseconds())
+		tv = &timeval
+	}
+	ready, err := unix.Select(fd+1, &fds, nil, nil, tv)
+	if err != nil {
+		if errors.Is(err, unix.EINTR) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if ready == 0 {
+		return 0, nil
+	}
+	return unix.Read(fd, p)
+}
+
 func (t *FileTerminal) IsTerminal() bool {
 	_, err := unix.IoctlGetTermios(t.Fd(), getTermios)
 	return err == nil
@@ -93,20 +152,47 @@ func (t *FileTerminal) SetState(termState *unix.Termios) error {
 // Screen represents a terminal screen with methods to manipulate the terminal display.
 type Screen struct {
 	terminal Terminal
-	// Width represents the width of the terminal in characters. It is updated on resize.
-	Width int
-	// Height represents the height of the terminal in characters. It is updated on resize.
-	Height int
+	// sizeMu guards the size of the terminal and the resize callbacks
+	sizeMu          sync.RWMutex
+	width           int
+	height          int
+	resizeCallbacks []func(width, height int)
 	// CursorVisible indicates whether the cursor is currently visible.
 	CursorVisible  bool
+	input          *inputReader
 	signals        chan os.Signal
 	initialTermios unix.Termios
 	closed         atomic.Bool
+	nextImageID    atomic.Uint32
+	// cellsMu guards the cell buffers: back is drawn by SetCell and similar methods, front is what the terminal shows
+	cellsMu sync.Mutex
+	back    *cellGrid
+	front   *cellGrid
+	redraw  bool
+	// mouseMode is the enabled mouse tracking mode
+	mouseMode MouseMode
+	// bracketedPaste is true if bracketed paste is enabled
+	bracketedPaste bool
+	// focusEvents is true if focus reporting is enabled
+	focusEvents bool
+	// kittyKeyboardPushes is the number of kitty keyboard flags pushed to the terminal's stack
+	kittyKeyboardPushes int
+	// cursorStyled and cursorColoured are true if the shape or the colour of the cursor was changed
+	cursorStyled   bool
+	cursorColoured bool
+	// closing is closed when the screen is closed
+	closing  chan struct{}
+	eventsMu sync.Mutex
+	events   *eventLoop
 }
 
-// NewScreen initializes a new Screen using the standard output file descriptor,
+// NewScreen initializes a new Screen writing to the standard output and reading the input from the standard input,
 func NewScreen() (*Screen, error) {
-	return NewScreenFromFile(os.Stdout)
+	term, err := NewTerminalFromFiles(os.Stdin, os.Stdout)
+	if err != nil {
+		return nil, NewScreenError("Cannot create terminal for standard streams", err)
+	}
+	return NewScreenFromTerminal(term)
 }
 
 // NewScreenFromTerminal initializes a new Screen using the provided Terminal interface,
@@ -117,7 +203,9 @@ func NewScreenFromTerminal(term Terminal) (*Screen, error) {
 	screen := &Screen{
 		terminal:      term,
 		CursorVisible: true,
+		input:         newInputReader(term),
 		signals:       make(chan os.Signal, 1),
+		closing:       make(chan struct{}),
 	}
 	screen.closed.Store(false)
 	termState, err := term.GetState()
@@ -128,23 +216,13 @@ func NewScreenFromTerminal(term Terminal) (*Screen, error) {
 	screen.initialTermios = *termState
 	screen.enterAlternateBuffer()
 	screen.Clear()
-	err = screen.resize()
+	_, err = screen.resize()
 	if err != nil {
 		screen.Close()
 		return nil, err
 	}
 	signal.Notify(screen.signals, unix.SIGWINCH, unix.SIGTERM, unix.SIGINT)
-	go func() {
-		for sig := range screen.signals {
-			switch sig {
-			case unix.SIGWINCH:
-				_ = screen.resize()
-			case unix.SIGTERM, unix.SIGINT:
-				screen.Close()
-				return
-			}
-		}
-	}()
+	go screen.handleSignals()
 	return screen, nil
 }
 
@@ -163,14 +241,98 @@ func (s *Screen) writeEsc(command string) {
 	_, _ = s.terminal.Write(command)
 }
 
-func (s *Screen) resize() error {
+func (s *Screen) write(text string) {
+	_, _ = s.terminal.Write(text)
+}
+
+// resize reads the size of the terminal and returns true if it changed
+func (s *Screen) resize() (bool, error) {
 	winSize, err := s.terminal.GetSize()
 	if err != nil {
-		return NewScreenError("Cannot get window size", err)
+		return false, NewScreenError("Cannot get window size", err)
 	}
-	s.Width = int(winSize.Col)
-	s.Height = int(winSize.Row)
-	return nil
+	s.sizeMu.Lock()
+	defer s.sizeMu.Unlock()
+	width, height := int(winSize.Col), int(winSize.Row)
+	changed := width != s.width || height != s.height
+	s.width, s.height = width, height
+	return changed, nil
+}
+
+func (s *Screen) handleSignals() {
+	var debounce <-chan time.Time
+	for {
+		select {
+		case sig, ok := <-s.signals:
+			if !ok {
+				return
+			}
+			switch sig {
+			case unix.SIGWINCH:
+				// terminals send a burst of signals while the window is being resized, only the last one is handled
+				debounce = time.After(resizeDebounce)
+			case unix.SIGTERM, unix.SIGINT:
+				if !s.postSignal(sig) {
+					s.Close()
+					return
+				}
+			}
+		case <-debounce:
+			debounce = nil
+			s.handleResize()
+		}
+	}
+}
+
+// handleResize updates the size of the screen, redraws the cell buffer if it is used and notifies
+// the resize callbacks and the event loop
+func (s *Screen) handleResize() {
+	changed, err := s.resize()
+	if err != nil || !changed {
+		return
+	}
+	width, height := s.Size()
+	s.sizeMu.RLock()
+	callbacks := slices.Clone(s.resizeCallbacks)
+	s.sizeMu.RUnlock()
+	for _, callback := range callbacks {
+		callback(width, height)
+	}
+	s.postResize()
+}
+
+// Size returns the width and the height of the terminal in characters. The size is updated when the terminal
+// is resized.
+func (s *Screen) Size() (width, height int) {
+	s.sizeMu.RLock()
+	defer s.sizeMu.RUnlock()
+	return s.width, s.height
+}
+
+// Width returns the width of the terminal in characters.
+//
+// Deprecated: use Size instead, it returns both dimensions consistently.
+func (s *Screen) Width() int {
+	width, _ := s.Size()
+	return width
+}
+
+// Height returns the height of the terminal in characters.
+//
+// Deprecated: use Size instead.
+func (s *Screen) Height() int {
+	_, height := s.Size()
+	return height
+}
+
+// OnResize registers the callback that is called with the new size of the terminal when it is resized.
+// Bursts of resizes are reported once, after the size stops changing. Callbacks are called from a separate
+// goroutine, so they must not write to the terminal while the application does. If the cell buffer is used,
+// the next Show resizes it and redraws the whole screen, call it after receiving ResizeEvent from Events.
+func (s *Screen) OnResize(callback func(width, height int)) {
+	s.sizeMu.Lock()
+	defer s.sizeMu.Unlock()
+	s.resizeCallbacks = append(s.resizeCallbacks, callback)
 }
 
 // SetCursorVisible shows or hides the cursor in the terminal.
@@ -189,22 +351,87 @@ func (s *Screen) SetCursorVisible(visible bool) {
 // MoveCursorTo moves the cursor to the specified (x, y) position in the terminal.
 // Coordinates are 1-based, where (1, 1) is the top-left corner
 func (s *Screen) MoveCursorTo(x, y int) {
-	if x < 1 || y < 1 || x > s.Width || y > s.Height {
+	width, height := s.Size()
+	if x < 1 || y < 1 || x > width || y > height {
 		return // Invalid coordinates
 	}
 	s.writeEsc(fmt.Sprintf("%d;%dH", y, x)) // Move cursor to (x, y)
 }
 
+// Print writes text to the terminal at the current cursor position.
+func (s *Screen) Print(text string) {
+	s.write(text)
+}
+
+// PrintAt moves the cursor to the specified (x, y) position and writes text to the terminal.
+// Coordinates are 1-based, where (1, 1) is the top-left corner. Nothing is written if the position is outside
+// of the screen
+func (s *Screen) PrintAt(x, y int, text string) {
+	if s.checkPosition(x, y) != nil {
+		return
+	}
+	s.MoveCursorTo(x, y)
+	s.write(text)
+}
+
+// PrintBlockAt writes multi-line text to the terminal, so that each line starts in the column x. The first line
+// is written in the row y. Lines that don't fit on the screen are skipped, nothing is written if the column x
+// is outside of the screen. Coordinates are 1-based.
+// This is useful to draw the output of Canvas, tables or charts into a region of the screen.
+func (s *Screen) PrintBlockAt(x, y int, text string) {
+	width, height := s.Size()
+	if x < 1 || x > width {
+		return
+	}
+	for i, line := range strings.Split(text, "\n") {
+		if y+i > height {
+			return
+		}
+		s.PrintAt(x, y+i, line)
+	}
+}
+
+// DrawInlineImage displays the image using iTerm2 inline images protocol with the top-left corner in the cell (x, y).
+// Coordinates are 1-based, where (1, 1) is the top-left corner
+func (s *Screen) DrawInlineImage(img *InlineImage, x, y int) error {
+	if err := s.checkPosition(x, y); err != nil {
+		return err
+	}
+	s.PrintAt(x, y, img.String())
+	return nil
+}
+
+func (s *Screen) checkPosition(x, y int) error {
+	width, height := s.Size()
+	if x < 1 || y < 1 || x > width || y > height {
+		return NewScreenError(fmt.Sprintf("Position (%d, %d) is outside of the screen", x, y), nil)
+	}
+	return nil
+}
+
 // Clear clears the terminal screen and moves the cursor to the home position.
 func (s *Screen) Clear() {
 	s.writeEsc("2J") // Clear the screen
 	s.writeEsc("H")  // Move cursor to home position
+	s.resetCells()
 }
 
 // Close closes the screen, restores the terminal state, and exits alternate buffer mode.
 func (s *Screen) Close() {
 	if s.closed.CompareAndSwap(false, true) {
+		signal.Stop(s.signals)
 		close(s.signals)
+		close(s.closing)
+		s.DisableMouse()
+		s.DisableBracketedPaste()
+		s.DisableFocusEvents()
+		s.PopKittyKeyboard(s.kittyKeyboardPushes)
+		if s.cursorStyled {
+			s.SetCursorStyle(CursorDefault, false)
+		}
+		if s.cursorColoured {
+			s.ResetCursorColour()
+		}
 		// Ensure we are in the alternate buffer before cleanup to maintain consistent terminal state
 		s.enterAlternateBuffer()
 		s.SetCursorVisible(true)
@@ -221,6 +448,20 @@ func (s *Screen) exitAlternateBuffer() {
 	s.writeEsc("?1049l")
 }
 
+// ReadEvent waits at most timeout for an input event, like a key press, and returns it. Negative timeout waits
+// indefinitely. If the timeout expires, it returns nil event and nil error.
+// The terminal must be in raw mode to receive the keys as soon as they are pressed, see SetRawMode.
+func (s *Screen) ReadEvent(timeout time.Duration) (Event, error) {
+	return s.input.readEvent(timeout)
+}
+
+// SetEscapeTimeout sets the time to wait for the rest of an escape sequence after ESC is received. If nothing
+// arrives in time, ESC is reported as the Esc key, otherwise the sequence is decoded as a single key, like an arrow
+// key or Alt combination. Defaults to 50ms, increase it for slow connections.
+func (s *Screen) SetEscapeTimeout(timeout time.Duration) {
+	s.input.setEscapeTimeout(timeout)
+}
+
 // SetRawMode sets the terminal to raw mode or restores it to normal mode.
 // In raw mode, input is not processed (no echo, no line buffering).
 // This is useful for applications that need to handle input directly, like text editors or games.
@@ -3,6 +3,7 @@
 package ansie
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -18,8 +19,9 @@ func TestNewScreen(t *testing.T) {
 		defer s.Close()
 	}
 	g.Expect(err).To(BeNil(), "Expected no error when creating a new screen")
-	g.Expect(s.Width).To(Equal(80), "Expected terminal width to be 80")
-	g.Expect(s.Height).To(Equal(24), "Expected terminal height to be 24")
+	width, height := s.Size()
+	g.Expect(width).To(Equal(80), "Expected terminal width to be 80")
+	g.Expect(height).To(Equal(24), "Expected terminal height to be 24")
 	state, err := m.GetState()
 	g.Expect(err).To(BeNil(), "Expected no error when reading terminal state")
 	lflag := uint32(state.Lflag)
@@ -53,8 +55,32 @@ func TestScreenResize(t *testing.T) {
 	}
 	m.SetSize(100, 30, s.signals)
 	time.Sleep(100 * time.Millisecond) // Allow time for resize signal to be processed
-	g.Expect(s.Width).To(Equal(100), "Expected terminal width to be updated")
-	g.Expect(s.Height).To(Equal(30), "Expected terminal height to be updated")
+	width, height := s.Size()
+	g.Expect(width).To(Equal(100), "Expected terminal width to be updated")
+	g.Expect(height).To(Equal(30), "Expected terminal height to be updated")
+}
+
+func TestScreen_OnResize(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 10, 2)
+	defer s.Close()
+
+	sizes := make(chan [2]int, 10)
+	s.OnResize(func(width, height int) {
+		sizes <- [2]int{width, height}
+	})
+	s.SetString(1, 1, "hello", NewStyle())
+	s.Show()
+	m.ResetBuffer()
+
+	m.SetSize(20, 3, s.signals)
+	m.SetSize(30, 4, s.signals)
+	g.Eventually(sizes).Should(Receive(Equal([2]int{30, 4})))
+	g.Consistently(sizes, 100*time.Millisecond).ShouldNot(Receive(), "Expected burst of resizes to be reported once")
+	g.Expect(m.Output()).To(BeEmpty(), "Expected nothing to be written from the signal handler")
+	s.Show()
+	g.Expect(m.Output()).To(ContainSubstring("\033[2J\033[1;1Hhello"), "Expected cell buffer to be redrawn")
+	g.Expect(s.GetCell(30, 4)).To(Equal(blankCell), "Expected cell buffer to be reallocated")
 }
 
 func TestScreen_SetRawMode(t *testing.T) {
@@ -106,3 +132,103 @@ func TestScreen_MoveCursorTo(t *testing.T) {
 	s.MoveCursorTo(80, 24)
 	g.Expect(m.Buffer.String()).To(ContainSubstring("\u001B[24;80H"), "Expected cursor to move to (80, 24)")
 }
+
+func TestScreen_PrintAt(t *testing.T) {
+	g := NewGomegaWithT(t)
+	m := NewMockTerminal(80, 24)
+	s, err := NewScreenFromTerminal(m)
+	if err == nil {
+		defer s.Close()
+	}
+	g.Expect(err).To(BeNil(), "Expected no error when creating a new screen")
+	m.ResetBuffer()
+	s.PrintAt(3, 4, "text")
+	s.Print("!")
+	g.Expect(m.Buffer.String()).To(Equal("\u001B[4;3Htext!"))
+}
+
+func TestScreen_PrintAtOutside(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 10, 5)
+	defer s.Close()
+
+	s.PrintAt(11, 2, "X")
+	s.PrintAt(0, 2, "X")
+	s.PrintAt(3, 6, "X")
+	s.PrintAt(3, 0, "X")
+	g.Expect(m.Buffer.String()).To(BeEmpty(), "Expected nothing to be written outside of the screen")
+}
+
+func TestScreen_DrawInlineImage(t *testing.T) {
+	g := NewGomegaWithT(t)
+	m := NewMockTerminal(80, 24)
+	s, err := NewScreenFromTerminal(m)
+	if err == nil {
+		defer s.Close()
+	}
+	g.Expect(err).To(BeNil(), "Expected no error when creating a new screen")
+	m.ResetBuffer()
+	img := NewInlineImage([]byte("data"))
+	g.Expect(s.DrawInlineImage(img, 10, 2)).To(BeNil())
+	g.Expect(m.Buffer.String()).To(Equal("\u001B[2;10H" + img.String()))
+	g.Expect(s.DrawInlineImage(img, 10, 25)).ToNot(BeNil(), "Expected error when drawing outside of the screen")
+}
+
+func TestScreen_PrintBlockAt(t *testing.T) {
+	g := NewGomegaWithT(t)
+	m := NewMockTerminal(80, 24)
+	s, err := NewScreenFromTerminal(m)
+	if err == nil {
+		defer s.Close()
+	}
+	g.Expect(err).To(BeNil(), "Expected no error when creating a new screen")
+	m.ResetBuffer()
+	s.PrintBlockAt(5, 23, "line1\nline2\nline3")
+	g.Expect(m.Buffer.String()).To(Equal("\u001B[23;5Hline1\u001B[24;5Hline2"))
+}
+
+func TestScreen_PrintBlockAtOutside(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, m := newTestScreen(g, 10, 5)
+	defer s.Close()
+
+	s.PrintBlockAt(20, 1, "a\nb")
+	g.Expect(m.Buffer.String()).To(BeEmpty(), "Expected nothing to be written outside of the screen")
+	s.PrintBlockAt(2, 0, "a\nb")
+	g.Expect(m.Buffer.String()).To(Equal("\u001B[1;2Hb"), "Expected lines above the screen to be skipped")
+}
+
+// writeOnlyTerminal hides Read method of the wrapped terminal
+type writeOnlyTerminal struct {
+	Terminal
+}
+
+func TestScreen_WithoutInput(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, err := NewScreenFromTerminal(writeOnlyTerminal{NewMockTerminal(80, 24)})
+	g.Expect(err).To(BeNil())
+	defer s.Close()
+
+	_, err = s.ReadEvent(time.Millisecond)
+	g.Expect(errors.Is(err, ErrNoInput)).To(BeTrue())
+	_, _, err = s.CursorPosition()
+	g.Expect(errors.Is(err, ErrNoInput)).To(BeTrue())
+}
+
+func TestFitScreen(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, _ := newTestScreen(g, 100, 30)
+	defer s.Close()
+
+	g.Expect((&Table{}).FitScreen(s).Width).To(Equal(100))
+	g.Expect((&DiffRenderer{}).FitScreen(s).Width).To(Equal(100))
+}
+
+func TestScreen_DeprecatedSize(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s, _ := newTestScreen(g, 100, 30)
+	defer s.Close()
+
+	g.Expect(s.Width()).To(Equal(100))
+	g.Expect(s.Height()).To(Equal(30))
+}
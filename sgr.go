@@ -0,0 +1,193 @@
+package ansie
+
+import (
+	"strings"
+)
+
+// offCodes are the SGR codes that switch the attributes off. Some codes switch off more than one attribute
+var offCodes = map[Attribute]int{
+	Bold:       Normal,
+	Faint:      Normal,
+	Italic:     NoItalic,
+	Underline:  NoUnderline,
+	SlowBlink:  NoBlink,
+	RapidBlink: NoBlink,
+	Reverse:    NoReverse,
+	Conceal:    NoConceal,
+	CrossOut:   NoCrossOut,
+}
+
+// OptimiseSgr rewrites SGR sequences in s to produce the same output with fewer bytes. Consecutive SGR
+// sequences are combined into one, sequences that don't change the colours and attributes are removed and
+// changes are written as a difference from the current state, using "No" attributes or reset, whichever is shorter.
+//
+// s is assumed to start with the default colours and attributes. Colours are kept as they are, but may be
+// written in a different form, for example 38;5;9 is written as 91. If true colour and 256-colour sequences are
+// written for the same colour, as in compatibility mode, only the true colour is kept.
+// SGR sequences with codes that are not supported by Style, like "4:3" or "53", are kept as they are.
+func OptimiseSgr(s string) string {
+	o := sgrOptimiser{}
+	return o.optimise(s)
+}
+
+// sgrOptimiser keeps the state of the terminal between the optimised fragments of the output
+type sgrOptimiser struct {
+	// state is the style in effect after the output optimised so far
+	state Style
+	// compat writes true colours in compatibility mode
+	compat bool
+}
+
+func (o *sgrOptimiser) optimise(s string) string {
+	var sb strings.Builder
+	pending := o.state
+	flush := func() {
+		if pending != o.state {
+			sb.WriteString(o.diff(pending))
+			o.state = pending
+		}
+	}
+	start := 0
+	for i := 0; i < len(s); {
+		n := escapeLength(s[i:])
+		if n == 0 {
+			i++
+			continue
+		}
+		seq := s[i : i+n]
+		if params, ok := sgrParams(seq); ok && sgrSupported(params) {
+			if start < i {
+				flush()
+				sb.WriteString(s[start:i])
+			}
+			pending = pending.applySgr(params)
+		} else {
+			// other sequences, like clearing the line, may depend on the current colours
+			flush()
+			sb.WriteString(s[start : i+n])
+			if ok {
+				o.state = o.state.applySgr(params)
+				pending = o.state
+			}
+		}
+		i += n
+		start = i
+	}
+	if start < len(s) {
+		flush()
+		sb.WriteString(s[start:])
+	}
+	flush()
+	return sb.String()
+}
+
+// diff returns the shortest SGR sequence that changes the current state to the style
+func (o *sgrOptimiser) diff(style Style) string {
+	a := &AnsiBuffer{enabled: true, profile: TrueColour, ColorCompatibility: o.compat}
+	if style.IsDefault() {
+		return a.Reset().String()
+	}
+	full := a.EscM(append([]int{Reset}, a.styleCodes(style)...)...).String()
+
+	var codes []int
+	cleared := map[int]bool{}
+	for attr := Bold; attr <= CrossOut; attr++ {
+		if o.state.HasAttr(attr) && !style.HasAttr(attr) && !cleared[offCodes[attr]] {
+			codes = append(codes, offCodes[attr])
+			cleared[offCodes[attr]] = true
+		}
+	}
+	for attr := Bold; attr <= CrossOut; attr++ {
+		if style.HasAttr(attr) && (!o.state.HasAttr(attr) || cleared[offCodes[attr]]) {
+			codes = append(codes, attr)
+		}
+	}
+	if style.fg != o.state.fg {
+		if style.fg.kind == defaultColour {
+			codes = append(codes, 39)
+		} else {
+			codes = append(codes, a.colourCodes(30, style.fg)...)
+		}
+	}
+	if style.bg != o.state.bg {
+		if style.bg.kind == defaultColour {
+			codes = append(codes, 49)
+		} else {
+			codes = append(codes, a.colourCodes(40, style.bg)...)
+		}
+	}
+	incremental := a.EscM(codes...).String()
+	if len(incremental) < len(full) {
+		return incremental
+	}
+	return full
+}
+
+// sgrParams returns the parameters of "CSI params m" sequence
+func sgrParams(seq string) (string, bool) {
+	if !strings.HasPrefix(seq, esc) || !strings.HasSuffix(seq, "m") {
+		return "", false
+	}
+	params := seq[len(esc) : len(seq)-1]
+	if strings.ContainsFunc(params, func(r rune) bool { return (r < '0' || r > '9') && r != ';' && r != ':' }) {
+		// private sequences, like "CSI > 4;2 m"
+		return "", false
+	}
+	return params, true
+}
+
+// sgrSupported checks if all the SGR codes can be represented by Style
+func sgrSupported(params string) bool {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		sub := strings.Split(codes[i], ":")
+		switch code := sub[0]; code {
+		case "38", "48":
+			if len(sub) > 1 {
+				if sub[1] != "5" && sub[1] != "2" {
+					return false
+				}
+				continue
+			}
+			if i+1 >= len(codes) {
+				return false
+			}
+			switch codes[i+1] {
+			case "5":
+				i += 2
+			case "2":
+				i += 4
+			default:
+				return false
+			}
+			if i >= len(codes) {
+				return false
+			}
+		default:
+			if len(sub) > 1 || !supportedSgrCode(code) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func supportedSgrCode(code string) bool {
+	if code == "" {
+		return true
+	}
+	n := 0
+	for _, c := range code {
+		n = n*10 + int(c-'0')
+		if n > 107 {
+			return false
+		}
+	}
+	switch {
+	case n <= CrossOut, n >= NoBold && n <= NoCrossOut && n != 26:
+		return true
+	case n >= 30 && n <= 49, n >= 90 && n <= 97, n >= 100 && n <= 107:
+		return true
+	}
+	return false
+}
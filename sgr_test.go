@@ -0,0 +1,47 @@
+package ansie
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestOptimiseSgr(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(OptimiseSgr("\033[31m\033[1m\033[44mtext\033[0m")).To(Equal("\033[1;31;44mtext\033[0m"),
+		"Expected consecutive sequences to be combined")
+	g.Expect(OptimiseSgr("\033[31ma\033[31mb\033[0m\033[0mc")).To(Equal("\033[31mab\033[0mc"),
+		"Expected sequences without changes to be removed")
+	g.Expect(OptimiseSgr("\033[1;31ma\033[0m\033[31mb")).To(Equal("\033[1;31ma\033[22mb"),
+		"Expected only the difference to be written")
+	g.Expect(OptimiseSgr("\033[1;2;31ma\033[0m\033[2mb\033[0m")).To(Equal("\033[1;2;31ma\033[0;2mb\033[0m"),
+		"Expected reset when it is shorter")
+	g.Expect(OptimiseSgr("\033[32m\033[0mplain")).To(Equal("plain"))
+	g.Expect(OptimiseSgr("\033[38;5;9m\033[48:2::1:2:3mx\033[m")).To(Equal("\033[91;48;2;1;2;3mx\033[0m"))
+}
+
+func TestOptimiseSgr_OtherSequences(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(OptimiseSgr("\033[41m\033[2K\033[0m\033[5;1Hx")).To(Equal("\033[41m\033[2K\033[0m\033[5;1Hx"),
+		"Expected the style to be applied before other sequences")
+	g.Expect(OptimiseSgr("\033[1m\033[4:3mx\033[0m")).To(Equal("\033[1m\033[4:3mx\033[0m"),
+		"Expected unsupported codes to be kept")
+	g.Expect(OptimiseSgr("\033[>4;2mx")).To(Equal("\033[>4;2mx"))
+}
+
+func TestAnsiBuffer_SgrOptimisation(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	a.SgrOptimisation = true
+	g.Expect(a.Fg(Red).Attr(Bold).Bg(Blue).A("a").String()).To(Equal("\033[1;31;44ma"))
+	g.Expect(a.Fg(Red).A("b").Reset().String()).To(Equal("b\033[0m"), "Expected the state to be kept between strings")
+
+	a.ColorCompatibility = true
+	g.Expect(a.FgRgb(255, 0, 0).A("c").String()).To(Equal("\033[38;5;196;38;2;255;0;0mc"))
+
+	a.SetEnabled(false)
+	g.Expect(a.Fg(Red).A("d").String()).To(Equal("d"))
+}
@@ -0,0 +1,258 @@
+package ansie
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	defaultSlogTimeFormat   = "15:04:05.000"
+	defaultSlogMessageWidth = 40
+)
+
+// DefaultLevelStyles are the styles of the level badges used by SlogHandler if no styles are set
+var DefaultLevelStyles = map[slog.Level]Style{
+	slog.LevelDebug: NewStyle().Fg(Black).Bg(Blue),
+	slog.LevelInfo:  NewStyle().Fg(Black).Bg(Green),
+	slog.LevelWarn:  NewStyle().Fg(Black).Bg(Yellow),
+	slog.LevelError: NewStyle().FgHi(White).Bg(Red).Attr(Bold),
+}
+
+// SlogHandlerOptions configure SlogHandler. The zero value uses the defaults
+type SlogHandlerOptions struct {
+	// Level is the minimum level of the records that are logged. Defaults to slog.LevelInfo
+	Level slog.Leveler
+	// AddSource adds the file name and line of the log call to the record
+	AddSource bool
+	// TimeFormat is the layout of the record time. Defaults to "15:04:05.000"
+	TimeFormat string
+	// MessageWidth is the width to which the message is padded so that the attributes are aligned. Defaults to 40
+	MessageWidth int
+	// LevelStyles are the styles of the level badges. A level without a style uses the style of the nearest
+	// lower level. Defaults to DefaultLevelStyles. The badges are as wide as the longest name of these levels and
+	// Level, so custom levels like slog.LevelWarn+2 should be listed here to keep the messages aligned
+	LevelStyles map[slog.Level]Style
+	// KeyStyle is the style of the attribute keys. Defaults to cyan
+	KeyStyle *Style
+}
+
+// SlogHandler is a slog.Handler printing human-friendly records with coloured level badges and aligned messages
+// followed by key=value attributes:
+//
+//	15:04:05.000  INF  Server started                      port=8080 env=prod
+//
+// Output is not coloured when the writer is not a terminal. The handler is safe for concurrent use.
+type SlogHandler struct {
+	opts   SlogHandlerOptions
+	out    io.Writer
+	mu     *sync.Mutex
+	colour bool
+	// width of the level names in the badges
+	levelWidth int
+	// groups opened with WithGroup, used as a prefix of the attribute keys
+	prefix string
+	// attributes added with WithAttrs, already formatted
+	attrs string
+}
+
+// NewSlogHandler creates a handler writing to w. If opts is nil, the default options are used
+func NewSlogHandler(w io.Writer, opts *SlogHandlerOptions) *SlogHandler {
+	h := &SlogHandler{out: w, mu: &sync.Mutex{}}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Level == nil {
+		h.opts.Level = slog.LevelInfo
+	}
+	if h.opts.TimeFormat == "" {
+		h.opts.TimeFormat = defaultSlogTimeFormat
+	}
+	if h.opts.MessageWidth <= 0 {
+		h.opts.MessageWidth = defaultSlogMessageWidth
+	}
+	if h.opts.LevelStyles == nil {
+		h.opts.LevelStyles = DefaultLevelStyles
+	}
+	h.levelWidth = len(levelName(h.opts.Level.Level()))
+	for level := range h.opts.LevelStyles {
+		h.levelWidth = max(h.levelWidth, len(levelName(level)))
+	}
+	if h.opts.KeyStyle == nil {
+		style := NewStyle().Fg(Cyan)
+		h.opts.KeyStyle = &style
+	}
+	if f, ok := w.(*os.File); ok {
+		h.colour = NewAnsiFor(f).IsEnabled()
+	}
+	return h
+}
+
+// Enabled reports whether the handler handles records at the given level
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Handle formats the record as a single line and writes it
+func (h *SlogHandler) Handle(_ context.Context, r slog.Record) error {
+	a := h.ansi()
+	if !r.Time.IsZero() {
+		a.WithStyle(NewStyle().Attr(Faint), r.Time.Format(h.opts.TimeFormat)).A(" ")
+	}
+	a.WithStyle(h.levelStyle(r.Level), " "+Align(levelName(r.Level), h.levelWidth, AlignLeft)+" ").A(" ")
+	if h.opts.AddSource && r.PC != 0 {
+		frame := sourceFrame(r.PC)
+		a.WithStyle(NewStyle().Attr(Faint), fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)).A(" ")
+	}
+
+	attrs := h.ansi()
+	attrs.A(h.attrs)
+	r.Attrs(func(attr slog.Attr) bool {
+		h.appendAttr(attrs, h.prefix, attr)
+		return true
+	})
+	formatted := attrs.String()
+	if formatted != "" {
+		a.A(Align(r.Message, h.opts.MessageWidth, AlignLeft)).A(formatted)
+	} else {
+		a.A(r.Message)
+	}
+	a.CR()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.out, a.String())
+	return err
+}
+
+// WithAttrs returns a handler that adds the attributes to every record
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := *h
+	a := h.ansi()
+	for _, attr := range attrs {
+		h.appendAttr(a, h.prefix, attr)
+	}
+	clone.attrs += a.String()
+	return &clone
+}
+
+// WithGroup returns a handler that qualifies the keys of the attributes added later with the group name
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix += name + "."
+	return &clone
+}
+
+func (h *SlogHandler) ansi() *AnsiBuffer {
+	a := NewAnsi()
+	a.SetEnabled(h.colour)
+	return a
+}
+
+// appendAttr adds " key=value" to the buffer. Groups are flattened to dot-separated keys
+func (h *SlogHandler) appendAttr(a *AnsiBuffer, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			h.appendAttr(a, prefix, member)
+		}
+		return
+	}
+	a.A(" ").WithStyle(*h.opts.KeyStyle, prefix+attr.Key+"=")
+	value := formatSlogValue(attr.Value)
+	if err, ok := attr.Value.Any().(error); ok && err != nil {
+		a.WithStyle(NewStyle().Fg(Red), value)
+	} else {
+		a.A(value)
+	}
+}
+
+// levelStyle finds the style of the level or the nearest lower level with a style
+func (h *SlogHandler) levelStyle(level slog.Level) Style {
+	var best slog.Level
+	var style Style
+	found := false
+	for l, s := range h.opts.LevelStyles {
+		if l <= level && (!found || l > best) {
+			best, style, found = l, s, true
+		}
+	}
+	return style
+}
+
+// levelName returns the short name of the level, like INF or WRN+2 for levels between the standard ones
+func levelName(level slog.Level) string {
+	name := func(base string, standard slog.Level) string {
+		if level == standard {
+			return base
+		}
+		return fmt.Sprintf("%s%+d", base, level-standard)
+	}
+	switch {
+	case level < slog.LevelInfo:
+		return name("DBG", slog.LevelDebug)
+	case level < slog.LevelWarn:
+		return name("INF", slog.LevelInfo)
+	case level < slog.LevelError:
+		return name("WRN", slog.LevelWarn)
+	default:
+		return name("ERR", slog.LevelError)
+	}
+}
+
+func formatSlogValue(v slog.Value) string {
+	var s string
+	switch v.Kind() {
+	case slog.KindString:
+		s = v.String()
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok && err != nil {
+			s = err.Error()
+		} else {
+			s = v.String()
+		}
+	default:
+		return v.String()
+	}
+	if needsQuoting(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	return strings.ContainsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r)
+	})
+}
+
+func sourceFrame(pc uintptr) runtime.Frame {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return frame
+}
@@ -0,0 +1,131 @@
+package ansie
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSlogHandler_Format(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	out := &bytes.Buffer{}
+	h := NewSlogHandler(out, &SlogHandlerOptions{MessageWidth: 10})
+	record := slog.NewRecord(time.Date(2024, 1, 1, 12, 30, 15, 0, time.UTC), slog.LevelWarn, "disk", 0)
+	record.AddAttrs(slog.Int("free", 10), slog.String("path", "/var/my data"), slog.Any("err", errors.New("full")))
+	g.Expect(h.Handle(context.Background(), record)).To(Succeed())
+	g.Expect(out.String()).To(Equal(`12:30:15.000  WRN  disk       free=10 path="/var/my data" err=full` + "\n"))
+
+	out.Reset()
+	logger := slog.New(h.WithAttrs([]slog.Attr{slog.String("svc", "api")}).WithGroup("req"))
+	logger.Info("handled", "id", 7, slog.Group("user", "name", "bob"), slog.Group("empty"))
+	g.Expect(out.String()).To(HaveSuffix("  INF  handled    svc=api req.id=7 req.user.name=bob\n"))
+
+	out.Reset()
+	logger.Debug("hidden")
+	g.Expect(out.String()).To(BeEmpty())
+	slog.New(h).Log(context.Background(), slog.LevelError+2, "no attrs")
+	g.Expect(out.String()).To(HaveSuffix("  ERR+2  no attrs\n"))
+}
+
+func TestSlogHandler_CustomLevels(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	out := &bytes.Buffer{}
+	styles := map[slog.Level]Style{slog.LevelInfo: NewStyle(), slog.LevelWarn + 2: NewStyle()}
+	logger := slog.New(NewSlogHandler(out, &SlogHandlerOptions{LevelStyles: styles}))
+	logger.Info("first", "n", 1)
+	logger.Log(context.Background(), slog.LevelWarn+2, "second", "n", 2)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	g.Expect(lines).To(HaveLen(2))
+	g.Expect(lines[0]).To(HaveSuffix("  INF    first" + strings.Repeat(" ", 36) + "n=1"))
+	g.Expect(lines[1]).To(HaveSuffix("  WRN+2  second" + strings.Repeat(" ", 35) + "n=2"))
+	g.Expect(StringWidth(lines[0])).To(Equal(StringWidth(lines[1])), "Expected messages to be aligned")
+}
+
+func TestSlogHandler_Colours(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	out := &bytes.Buffer{}
+	h := NewSlogHandler(out, &SlogHandlerOptions{Level: slog.LevelDebug})
+	h.colour = true
+	record := slog.NewRecord(time.Time{}, slog.LevelDebug+1, "msg", 0)
+	record.AddAttrs(slog.Any("err", errors.New("x")))
+	g.Expect(h.Handle(context.Background(), record)).To(Succeed())
+	g.Expect(out.String()).To(Equal("\033[30;44m DBG+1 \033[0m msg" + strings.Repeat(" ", 37) +
+		" \033[36merr=\033[0m\033[31mx\033[0m\n"))
+}
+
+func TestSlogHandler_Concurrent(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	out := &syncBuffer{}
+	logger := slog.New(NewSlogHandler(out, nil))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.With("worker", i).Info("tick", "n", j)
+			}
+		}()
+	}
+	wg.Wait()
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	g.Expect(lines).To(HaveLen(800))
+	for _, line := range lines {
+		g.Expect(line).To(MatchRegexp(`^\S+  INF  tick +worker=\d n=\d+$`))
+	}
+}
+
+func TestSlogHandler_Conformance(t *testing.T) {
+	out := &bytes.Buffer{}
+	h := NewSlogHandler(out, nil)
+	results := func() []map[string]any {
+		var records []map[string]any
+		for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+			records = append(records, parseSlogLine(t, line))
+		}
+		return records
+	}
+	if err := slogtest.TestHandler(h, results); err != nil {
+		t.Error(err)
+	}
+}
+
+// parseSlogLine converts a line produced by SlogHandler with colours disabled to the map expected by slogtest
+func parseSlogLine(t *testing.T, line string) map[string]any {
+	record := map[string]any{}
+	fields := strings.Fields(line)
+	if _, err := time.Parse(defaultSlogTimeFormat, fields[0]); err == nil {
+		record[slog.TimeKey] = fields[0]
+		fields = fields[1:]
+	}
+	record[slog.LevelKey] = fields[0]
+	record[slog.MessageKey] = fields[1]
+	for _, field := range fields[2:] {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			t.Fatalf("unexpected field %q in %q", field, line)
+		}
+		group := record
+		path := strings.Split(key, ".")
+		for _, name := range path[:len(path)-1] {
+			if _, ok := group[name]; !ok {
+				group[name] = map[string]any{}
+			}
+			group = group[name].(map[string]any)
+		}
+		group[path[len(path)-1]] = value
+	}
+	return record
+}
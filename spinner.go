@@ -0,0 +1,192 @@
+package ansie
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultSpinnerInterval = 80 * time.Millisecond
+
+// Frame sets for the Spinner
+var (
+	SpinnerDots    = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	SpinnerBraille = []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}
+	SpinnerLine    = []string{"-", "\\", "|", "/"}
+	SpinnerArc     = []string{"◜", "◠", "◝", "◞", "◡", "◟"}
+)
+
+// Spinner is an activity indicator animated in place on the current line. The prefix and suffix messages can be
+// changed while the spinner is running. All methods are safe for concurrent use.
+//
+// When the output is not a terminal, the spinner is not drawn, only the final state set by Success or Fail
+// is printed as a plain line.
+type Spinner struct {
+	// Frames of the animation. Defaults to SpinnerDots
+	Frames []string
+	// Interval between the frames. Defaults to 80ms, which is also used if Interval is not positive
+	Interval time.Duration
+	// Style of the spinner frames
+	Style Style
+	// SuccessSymbol and SuccessStyle are shown in place of the spinner by Success
+	SuccessSymbol string
+	SuccessStyle  Style
+	// FailSymbol and FailStyle are shown in place of the spinner by Fail
+	FailSymbol string
+	FailStyle  Style
+
+	mu      sync.Mutex
+	out     io.Writer
+	ansi    *AnsiBuffer
+	prefix  string
+	suffix  string
+	frame   int
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewSpinner creates a spinner writing to w with the suffix message. The spinner is animated only if w is a terminal.
+func NewSpinner(w io.Writer, suffix string) *Spinner {
+	ansi := NewAnsi()
+	if f, ok := w.(*os.File); ok {
+		ansi = NewAnsiFor(f)
+	} else {
+		ansi.SetEnabled(false)
+	}
+	return &Spinner{
+		Frames:        SpinnerDots,
+		Interval:      defaultSpinnerInterval,
+		SuccessSymbol: "✔",
+		SuccessStyle:  NewStyle().Fg(Green),
+		FailSymbol:    "✖",
+		FailStyle:     NewStyle().Fg(Red),
+		out:           w,
+		ansi:          ansi,
+		suffix:        suffix,
+	}
+}
+
+// SetPrefix changes the message shown before the spinner
+func (s *Spinner) SetPrefix(prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prefix = prefix
+}
+
+// SetSuffix changes the message shown after the spinner
+func (s *Spinner) SetSuffix(suffix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.suffix = suffix
+}
+
+// Start starts the animation in the background
+func (s *Spinner) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil || !s.ansi.IsEnabled() {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+	s.render()
+	go s.run(s.stop, s.stopped)
+}
+
+// Stop stops the animation and clears the line
+func (s *Spinner) Stop() {
+	s.halt()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ansi.IsEnabled() {
+		s.write(s.ansi.LF().ClearEol().String())
+	}
+}
+
+// Success stops the animation and replaces the spinner with the success symbol. If message is not empty,
+// it replaces the suffix. The line is terminated with a line break
+func (s *Spinner) Success(message string) {
+	s.finish(s.SuccessSymbol, s.SuccessStyle, message)
+}
+
+// Fail stops the animation and replaces the spinner with the failure symbol. If message is not empty,
+// it replaces the suffix. The line is terminated with a line break
+func (s *Spinner) Fail(message string) {
+	s.finish(s.FailSymbol, s.FailStyle, message)
+}
+
+func (s *Spinner) finish(symbol string, style Style, message string) {
+	s.halt()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if message != "" {
+		s.suffix = message
+	}
+	a := s.ansi
+	indicator := a.WithStyle(style, symbol).String()
+	if a.IsEnabled() {
+		a.LF().ClearEol()
+	}
+	s.renderLine(a, indicator)
+	s.write(a.CR().String())
+}
+
+func (s *Spinner) halt() {
+	s.mu.Lock()
+	stop, stopped := s.stop, s.stopped
+	s.stop = nil
+	s.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-stopped
+	}
+}
+
+func (s *Spinner) run(stop chan struct{}, stopped chan struct{}) {
+	defer close(stopped)
+	interval := s.Interval
+	if interval <= 0 {
+		interval = defaultSpinnerInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.frame++
+			s.render()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// render draws the current frame over the current line
+func (s *Spinner) render() {
+	frame := ""
+	if len(s.Frames) > 0 {
+		frame = s.ansi.WithStyle(s.Style, s.Frames[s.frame%len(s.Frames)]).String()
+	}
+	s.ansi.LF().ClearEol()
+	s.renderLine(s.ansi, frame)
+	s.write(s.ansi.String())
+}
+
+// renderLine adds the prefix, the indicator and the suffix separated with spaces to the buffer
+func (s *Spinner) renderLine(a *AnsiBuffer, indicator string) {
+	var parts []string
+	for _, part := range []string{s.prefix, indicator, s.suffix} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	a.A(strings.Join(parts, " "))
+}
+
+func (s *Spinner) write(text string) {
+	_, _ = io.WriteString(s.out, text)
+}
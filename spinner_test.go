@@ -0,0 +1,92 @@
+package ansie
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSpinner_Terminal(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	out := &syncBuffer{}
+	s := NewSpinner(out, "working")
+	s.ansi.SetEnabled(true)
+	s.Frames = SpinnerLine
+	s.Interval = time.Millisecond
+	s.Start()
+	g.Eventually(out.String).Should(ContainSubstring("\r\033[K\\ working"))
+	s.SetPrefix("[1/2]")
+	g.Eventually(out.String).Should(ContainSubstring("\r\033[K[1/2] - working"))
+	s.Success("finished")
+	g.Expect(out.String()).To(HaveSuffix("\r\033[K[1/2] \033[32m✔\033[0m finished\n"))
+
+	output := out.String()
+	time.Sleep(5 * time.Millisecond)
+	g.Expect(out.String()).To(Equal(output), "Expected spinner to stop")
+}
+
+func TestSpinner_StopAndFail(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	out := &syncBuffer{}
+	s := NewSpinner(out, "")
+	s.ansi.SetEnabled(true)
+	s.Style = NewStyle().Fg(Cyan)
+	s.Start()
+	g.Expect(out.String()).To(Equal("\r\033[K\033[36m⠋\033[0m"))
+	s.Stop()
+	g.Expect(out.String()).To(HaveSuffix("\r\033[K"))
+
+	s.Fail("failed")
+	g.Expect(out.String()).To(HaveSuffix("\r\033[K\033[31m✖\033[0m failed\n"))
+}
+
+func TestSpinner_NotTerminal(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	out := &syncBuffer{}
+	s := NewSpinner(out, "loading")
+	s.Interval = time.Millisecond
+	s.Start()
+	time.Sleep(5 * time.Millisecond)
+	g.Expect(out.String()).To(BeEmpty())
+	s.Fail("")
+	g.Expect(out.String()).To(Equal("✖ loading\n"))
+	g.Expect(strings.Count(out.String(), "\r")).To(BeZero())
+}
+
+func TestSpinner_NonPositiveInterval(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	out := &syncBuffer{}
+	s := NewSpinner(out, "working")
+	s.ansi.SetEnabled(true)
+	s.Frames = SpinnerLine
+	s.Interval = -time.Second
+	s.Start()
+	g.Eventually(out.String).Should(ContainSubstring("\r\033[K\\ working"), "Expected default interval to be used")
+	s.Stop()
+}
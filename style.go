@@ -0,0 +1,295 @@
+package ansie
+
+import (
+	"strconv"
+	"strings"
+)
+
+type colourKind uint8
+
+const (
+	defaultColour colourKind = iota
+	indexedColour
+	rgbColour
+)
+
+// styleColour is a foreground or background colour of the Style
+type styleColour struct {
+	kind  colourKind
+	value uint32
+}
+
+// Style is a combination of colours and text attributes that can be applied to the text with AnsiBuffer.Style.
+// Style is a value type, all methods return the modified copy of the style. Zero value is the default
+// terminal style. Styles can be compared with ==
+type Style struct {
+	fg    styleColour
+	bg    styleColour
+	attrs uint16
+}
+
+// NewStyle creates a new Style with default colours and no attributes
+func NewStyle() Style {
+	return Style{}
+}
+
+// Fg sets foreground colour from the 256-colour palette
+func (s Style) Fg(colour Colour) Style {
+	s.fg = styleColour{kind: indexedColour, value: uint32(clip(uint(colour), 255))}
+	return s
+}
+
+// Bg sets background colour from the 256-colour palette
+func (s Style) Bg(colour Colour) Style {
+	s.bg = styleColour{kind: indexedColour, value: uint32(clip(uint(colour), 255))}
+	return s
+}
+
+// FgHi sets foreground colour to the high intensity version of one of standard 8 colours
+func (s Style) FgHi(colour Colour) Style {
+	if colour <= 7 {
+		colour += 8
+	}
+	return s.Fg(colour)
+}
+
+// BgHi sets background colour to the high intensity version of one of standard 8 colours
+func (s Style) BgHi(colour Colour) Style {
+	if colour <= 7 {
+		colour += 8
+	}
+	return s.Bg(colour)
+}
+
+// FgRgb sets foreground colour using "true colour" RGB colour
+func (s Style) FgRgb(r, g, b uint) Style {
+	s.fg = styleColour{kind: rgbColour, value: uint32(clip(r, 255)<<16 | clip(g, 255)<<8 | clip(b, 255))}
+	return s
+}
+
+// BgRgb sets background colour using "true colour" RGB colour
+func (s Style) BgRgb(r, g, b uint) Style {
+	s.bg = styleColour{kind: rgbColour, value: uint32(clip(r, 255)<<16 | clip(g, 255)<<8 | clip(b, 255))}
+	return s
+}
+
+// FgRgbI sets foreground colour using "true colour" RGB colour represented as a single integer
+func (s Style) FgRgbI(i uint) Style {
+	return s.FgRgb((i>>16)&0xFF, (i>>8)&0xFF, i&0xFF)
+}
+
+// BgRgbI sets background colour using "true colour" RGB colour represented as a single integer
+func (s Style) BgRgbI(i uint) Style {
+	return s.BgRgb((i>>16)&0xFF, (i>>8)&0xFF, i&0xFF)
+}
+
+// Attr adds the attribute to the style. Reset removes all colours and attributes, while "No" attributes,
+// like NoBold or NoUnderline, remove the corresponding attributes
+func (s Style) Attr(attr Attribute) Style {
+	switch {
+	case attr == Reset:
+		return Style{}
+	case attr >= Bold && attr <= CrossOut:
+		s.attrs |= 1 << attr
+	case attr == NoBold, attr == Normal:
+		s.attrs &^= 1<<Bold | 1<<Faint
+	case attr == NoItalic:
+		s.attrs &^= 1 << Italic
+	case attr == NoUnderline:
+		s.attrs &^= 1 << Underline
+	case attr == NoBlink:
+		s.attrs &^= 1<<SlowBlink | 1<<RapidBlink
+	case attr == NoReverse:
+		s.attrs &^= 1 << Reverse
+	case attr == NoConceal:
+		s.attrs &^= 1 << Conceal
+	case attr == NoCrossOut:
+		s.attrs &^= 1 << CrossOut
+	}
+	return s
+}
+
+// HasAttr checks if the attribute is set in the style
+func (s Style) HasAttr(attr Attribute) bool {
+	return attr >= Bold && attr <= CrossOut && s.attrs&(1<<attr) != 0
+}
+
+// NoFg resets foreground colour to the terminal default
+func (s Style) NoFg() Style {
+	s.fg = styleColour{}
+	return s
+}
+
+// NoBg resets background colour to the terminal default
+func (s Style) NoBg() Style {
+	s.bg = styleColour{}
+	return s
+}
+
+// IsDefault returns true if the style has default colours and no attributes
+func (s Style) IsDefault() bool {
+	return s == Style{}
+}
+
+// Merge returns a copy of the style with colours and attributes of other style applied on top of it.
+// Default colours of other style don't override the colours of this style.
+func (s Style) Merge(other Style) Style {
+	if other.fg.kind != defaultColour {
+		s.fg = other.fg
+	}
+	if other.bg.kind != defaultColour {
+		s.bg = other.bg
+	}
+	s.attrs |= other.attrs
+	return s
+}
+
+// Style sets colours and attributes of the style. It doesn't reset the colours and attributes that are
+// not defined in the style, use Reset before Style to apply the style exactly.
+// All codes are combined in a single escape sequence.
+func (ap *AnsiBuffer) Style(style Style) *AnsiBuffer {
+	codes := ap.styleCodes(style)
+	if len(codes) > 0 {
+		ap.writeAnsiSeq(codes...)
+	}
+	return ap
+}
+
+// WithStyle adds the text to the AnsiBuffer's buffer using the style and then resets the colours and attributes
+func (ap *AnsiBuffer) WithStyle(style Style, text string) *AnsiBuffer {
+	if style.IsDefault() {
+		return ap.A(text)
+	}
+	return ap.Style(style).A(text).Reset()
+}
+
+// withNestedStyle works like WithStyle, but also restores the style after every reset in the text, so that
+// the style stays in effect around the styled fragments of the text
+func (ap *AnsiBuffer) withNestedStyle(style Style, text string) *AnsiBuffer {
+	codes := ap.styleCodes(style)
+	if style.IsDefault() || !ap.enabled || len(codes) == 0 {
+		return ap.WithStyle(style, text)
+	}
+	seq := NewAnsi().EscM(codes...).String()
+	text = strings.ReplaceAll(text, esc+"0m", esc+"0m"+seq)
+	text = strings.ReplaceAll(text, esc+"m", esc+"m"+seq)
+	return ap.A(seq).A(text).Reset()
+}
+
+func (ap *AnsiBuffer) styleCodes(style Style) []int {
+	var codes []int
+	for attr := Bold; attr <= CrossOut; attr++ {
+		if style.HasAttr(attr) {
+			codes = append(codes, attr)
+		}
+	}
+	codes = append(codes, ap.colourCodes(30, style.fg)...)
+	codes = append(codes, ap.colourCodes(40, style.bg)...)
+	return codes
+}
+
+// colourCodes returns SGR codes for the style colour converted to the profile of AnsiBuffer.
+// base is 30 for the foreground and 40 for the background colour
+func (ap *AnsiBuffer) colourCodes(base int, c styleColour) []int {
+	switch c.kind {
+	case indexedColour:
+		colour := Colour(c.value)
+		if colour >= 8 && colour < 16 && ap.profile != NoColour {
+			return []int{base + 60 + colour - 8}
+		}
+		return ap.paletteColourCodes(base, colour)
+	case rgbColour:
+		r, g, b := uint(c.value>>16)&0xFF, uint(c.value>>8)&0xFF, uint(c.value)&0xFF
+		if ap.profile == TrueColour && ap.ColorCompatibility {
+			return append(ap.paletteColourCodes(base, Rgb6x6x6(r, g, b)), ap.rgbColourCodes(base, r, g, b)...)
+		}
+		return ap.rgbColourCodes(base, r, g, b)
+	default:
+		return nil
+	}
+}
+
+// applySgr returns the style with the SGR parameters applied, params is the parameter string of
+// the "CSI params m" sequence. Both semicolon and colon separated forms of extended colours are supported.
+// Unknown codes are ignored
+func (s Style) applySgr(params string) Style {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		sub := strings.Split(codes[i], ":")
+		code, err := strconv.Atoi(sub[0])
+		if err != nil {
+			if sub[0] != "" {
+				continue
+			}
+			code = 0 // empty parameter is the same as 0
+		}
+		switch {
+		case code == 38 || code == 48:
+			var colour styleColour
+			if len(sub) > 1 {
+				colour = parseExtendedColour(sub[1:], true)
+			} else {
+				var used int
+				colour, used = parseExtendedColourSemicolons(codes[i+1:])
+				i += used
+			}
+			if code == 38 {
+				s.fg = colour
+			} else {
+				s.bg = colour
+			}
+		case code >= 30 && code <= 37:
+			s = s.Fg(code - 30)
+		case code == 39:
+			s = s.NoFg()
+		case code >= 40 && code <= 47:
+			s = s.Bg(code - 40)
+		case code == 49:
+			s = s.NoBg()
+		case code >= 90 && code <= 97:
+			s = s.Fg(code - 90 + 8)
+		case code >= 100 && code <= 107:
+			s = s.Bg(code - 100 + 8)
+		default:
+			s = s.Attr(code)
+		}
+	}
+	return s
+}
+
+// parseExtendedColourSemicolons parses the parameters following 38 or 48 in "5;n" or "2;r;g;b" form and returns
+// the colour and the number of parameters used
+func parseExtendedColourSemicolons(params []string) (styleColour, int) {
+	if len(params) == 0 {
+		return styleColour{}, 0
+	}
+	used := 1
+	switch params[0] {
+	case "5":
+		used = 2
+	case "2":
+		used = 4
+	}
+	used = min(used, len(params))
+	return parseExtendedColour(params[:used], false), used
+}
+
+// parseExtendedColour parses the "5;n" or "2;r;g;b" parameters of extended colour. Colon separated form can
+// contain colour space identifier before the RGB values: "2:cs:r:g:b"
+func parseExtendedColour(params []string, colons bool) styleColour {
+	values := make([]uint, 0, len(params))
+	for _, p := range params {
+		v, _ := strconv.Atoi(p)
+		values = append(values, uint(max(v, 0)))
+	}
+	switch {
+	case len(values) >= 2 && values[0] == 5:
+		return Style{}.Fg(Colour(values[1])).fg
+	case len(values) >= 4 && values[0] == 2:
+		if colons && len(values) >= 5 {
+			values = values[1:]
+		}
+		return Style{}.FgRgb(values[1], values[2], values[3]).fg
+	}
+	return styleColour{}
+}
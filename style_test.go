@@ -0,0 +1,71 @@
+package ansie
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestStyle_Attr(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	s := NewStyle().Attr(Bold).Attr(Underline).Attr(Faint)
+	g.Expect(s.HasAttr(Bold)).To(BeTrue())
+	g.Expect(s.HasAttr(Underline)).To(BeTrue())
+	g.Expect(s.HasAttr(Italic)).To(BeFalse())
+
+	s = s.Attr(NoBold)
+	g.Expect(s.HasAttr(Bold)).To(BeFalse())
+	g.Expect(s.HasAttr(Faint)).To(BeFalse())
+	g.Expect(s.Attr(NoUnderline).IsDefault()).To(BeTrue())
+	g.Expect(NewStyle().Fg(Red).Attr(Italic).Attr(Reset)).To(Equal(NewStyle()))
+}
+
+func TestAnsiBuffer_Style(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	st := NewStyle().Attr(Bold).Fg(Red).BgRgb(0, 0, 255)
+	g.Expect(a.Style(st).A("text").String()).To(Equal("\033[1;31;48;2;0;0;255mtext"))
+
+	st = NewStyle().FgHi(Blue).Bg(DarkGoldenrod)
+	g.Expect(a.Style(st).A("text").String()).To(Equal("\033[94;48;5;136mtext"))
+
+	g.Expect(a.Style(NewStyle()).A("text").String()).To(Equal("text"))
+}
+
+func TestAnsiBuffer_StyleProfile(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	st := NewStyle().Attr(Italic).FgRgbI(0xFF0000).Bg(Grey93)
+	a.SetProfile(Colours256)
+	g.Expect(a.Style(st).String()).To(Equal("\033[3;38;5;196;48;5;255m"))
+	a.SetProfile(Colours16)
+	g.Expect(a.Style(st).String()).To(Equal("\033[3;91;47m"))
+	a.SetProfile(NoColour)
+	g.Expect(a.Style(st).String()).To(Equal("\033[3m"))
+	a.SetEnabled(false)
+	g.Expect(a.Style(st).String()).To(Equal(""))
+
+	a = NewAnsi()
+	a.ColorCompatibility = true
+	g.Expect(a.Style(NewStyle().FgRgb(255, 0, 0)).String()).To(Equal("\033[38;5;196;38;2;255;0;0m"))
+}
+
+func TestAnsiBuffer_WithStyle(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	g.Expect(a.WithStyle(NewStyle().Attr(Underline), "text").A("!").String()).To(Equal("\033[4mtext\033[0m!"))
+	g.Expect(a.WithStyle(NewStyle(), "text").String()).To(Equal("text"))
+}
+
+func TestStyle_Merge(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	base := NewStyle().Fg(Red).Bg(Blue)
+	merged := base.Merge(NewStyle().Fg(Green).Attr(Bold))
+	g.Expect(merged).To(Equal(NewStyle().Fg(Green).Bg(Blue).Attr(Bold)))
+	g.Expect(merged.NoFg().NoBg()).To(Equal(NewStyle().Attr(Bold)))
+}
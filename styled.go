@@ -0,0 +1,92 @@
+package ansie
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Styled is a value formatted with a style. It implements fmt.Formatter, so it can be used with any verb
+// in fmt.Printf and similar functions: the value is formatted as usual and then styled. Width is applied to
+// the visible text, so columns stay aligned:
+//
+//	fmt.Printf("%-20v %v\n", NewStyle().Fg(Red).Of(name), Attr(Bold, elapsed))
+//
+// By default the value is rendered with the settings of the Ansi instance, so it's printed as plain text
+// when the standard output is not a terminal. Use For to render it for a different AnsiBuffer.
+type Styled struct {
+	Value any
+	Style Style
+	ansi  *AnsiBuffer
+}
+
+// Of creates a Styled value with the style
+func (s Style) Of(value any) Styled {
+	return Styled{Value: value, Style: s}
+}
+
+// Fg creates a Styled value with the foreground colour
+func Fg(colour Colour, value any) Styled {
+	return NewStyle().Fg(colour).Of(value)
+}
+
+// Bg creates a Styled value with the background colour
+func Bg(colour Colour, value any) Styled {
+	return NewStyle().Bg(colour).Of(value)
+}
+
+// Attr creates a Styled value with the attribute
+func Attr(attr Attribute, value any) Styled {
+	return NewStyle().Attr(attr).Of(value)
+}
+
+// For returns a copy of the value rendered with the colour settings and profile of the AnsiBuffer
+func (s Styled) For(a *AnsiBuffer) Styled {
+	s.ansi = a
+	return s
+}
+
+// String returns the value formatted with %v and styled
+func (s Styled) String() string {
+	return s.render(fmt.Sprint(s.Value))
+}
+
+// Format implements fmt.Formatter. Flags and precision are applied to the value, width pads the visible text
+// with spaces. Zero padding is applied to the value as usual
+func (s Styled) Format(f fmt.State, verb rune) {
+	var format strings.Builder
+	format.WriteByte('%')
+	for _, flag := range "+# " {
+		if f.Flag(int(flag)) {
+			format.WriteRune(flag)
+		}
+	}
+	width, hasWidth := f.Width()
+	if hasWidth && f.Flag('0') && !f.Flag('-') {
+		format.WriteByte('0')
+		format.WriteString(strconv.Itoa(width))
+		hasWidth = false
+	}
+	if precision, ok := f.Precision(); ok {
+		format.WriteByte('.')
+		format.WriteString(strconv.Itoa(precision))
+	}
+	format.WriteRune(verb)
+	text := s.render(fmt.Sprintf(format.String(), s.Value))
+	if hasWidth {
+		alignment := AlignRight
+		if f.Flag('-') {
+			alignment = AlignLeft
+		}
+		text = Align(text, width, alignment)
+	}
+	_, _ = f.Write([]byte(text))
+}
+
+func (s Styled) render(text string) string {
+	a := s.ansi
+	if a == nil {
+		a = Ansi
+	}
+	return a.scratch().WithStyle(s.Style, text).String()
+}
@@ -0,0 +1,39 @@
+package ansie
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestStyled_Format(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	red := Fg(Red, "name").For(a)
+	g.Expect(fmt.Sprintf("%v!", red)).To(Equal("\033[31mname\033[0m!"))
+	g.Expect(fmt.Sprintf("[%-6v]", red)).To(Equal("[\033[31mname\033[0m  ]"))
+	g.Expect(fmt.Sprintf("[%6v]", red)).To(Equal("[  \033[31mname\033[0m]"))
+	g.Expect(fmt.Sprintf("[%.2s]", red)).To(Equal("[\033[31mna\033[0m]"))
+	g.Expect(fmt.Sprintf("[%-4v]", Attr(Bold, "日本").For(a))).To(Equal("[\033[1m日本\033[0m]"),
+		"Expected width to count visible cells")
+	g.Expect(fmt.Sprintf("%05.1f", Bg(Blue, 3.14159).For(a))).To(Equal("\033[44m003.1\033[0m"))
+	g.Expect(fmt.Sprintf("%+d %x %q", Fg(Red, 5).For(a), Fg(Red, 255).For(a), Fg(Red, "s").For(a))).
+		To(Equal("\033[31m+5\033[0m \033[31mff\033[0m \033[31m\"s\"\033[0m"))
+}
+
+func TestStyled_String(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	a := NewAnsi()
+	g.Expect(NewStyle().Fg(Green).Attr(Bold).Of(42).For(a).String()).To(Equal("\033[1;32m42\033[0m"))
+
+	a.SetEnabled(false)
+	g.Expect(fmt.Sprintf("%-4v|", Fg(Red, "x").For(a))).To(Equal("x   |"), "Expected plain text when colours are disabled")
+	g.Expect(Fg(Red, "x").For(a).String()).To(Equal("x"))
+
+	a = NewAnsi()
+	a.SetProfile(Colours16)
+	g.Expect(NewStyle().FgRgb(255, 0, 0).Of("x").For(a).String()).To(Equal("\033[91mx\033[0m"))
+}
@@ -0,0 +1,152 @@
+package ansie
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TextSpan is a fragment of StyledText with a single style
+type TextSpan struct {
+	Text  string
+	Style Style
+}
+
+// StyledText is a text with styled spans. Unlike strings with embedded escape sequences, it can be safely sliced,
+// split and concatenated. StyledText is a value type, all the methods return a new StyledText.
+// Positions and lengths are measured in terminal cells.
+type StyledText struct {
+	spans []TextSpan
+}
+
+// NewStyledText creates a StyledText with a single span
+func NewStyledText(text string, style Style) StyledText {
+	return StyledText{}.Append(text, style)
+}
+
+// ParseAnsi converts a string with SGR escape sequences to StyledText. Other escape sequences are removed
+func ParseAnsi(s string) StyledText {
+	var t StyledText
+	var style Style
+	start := 0
+	for i := 0; i < len(s); {
+		n := escapeLength(s[i:])
+		if n == 0 {
+			_, size := utf8.DecodeRuneInString(s[i:])
+			i += size
+			continue
+		}
+		t = t.Append(s[start:i], style)
+		seq := s[i : i+n]
+		if strings.HasPrefix(seq, esc) && strings.HasSuffix(seq, "m") {
+			style = style.applySgr(seq[len(esc) : len(seq)-1])
+		}
+		i += n
+		start = i
+	}
+	return t.Append(s[start:], style)
+}
+
+// Spans returns the spans of the text. Adjacent spans always have different styles
+func (t StyledText) Spans() []TextSpan {
+	return append([]TextSpan(nil), t.spans...)
+}
+
+// Append returns the text with the string added at the end using the style
+func (t StyledText) Append(text string, style Style) StyledText {
+	if text == "" {
+		return t
+	}
+	spans := make([]TextSpan, len(t.spans), len(t.spans)+1)
+	copy(spans, t.spans)
+	if n := len(spans); n > 0 && spans[n-1].Style == style {
+		spans[n-1].Text += text
+	} else {
+		spans = append(spans, TextSpan{Text: text, Style: style})
+	}
+	return StyledText{spans: spans}
+}
+
+// Concat returns the text with the other texts added at the end
+func (t StyledText) Concat(others ...StyledText) StyledText {
+	for _, other := range others {
+		for _, span := range other.spans {
+			t = t.Append(span.Text, span.Style)
+		}
+	}
+	return t
+}
+
+// Len returns the width of the text in terminal cells
+func (t StyledText) Len() int {
+	n := 0
+	for _, span := range t.spans {
+		n += StringWidth(span.Text)
+	}
+	return n
+}
+
+// Slice returns the part of the text between the cells start and end, end is exclusive. Wide characters that
+// don't fit entirely between start and end are not included
+func (t StyledText) Slice(start, end int) StyledText {
+	var result StyledText
+	pos := 0
+	for _, span := range t.spans {
+		var sb strings.Builder
+		for _, r := range span.Text {
+			w := RuneWidth(r)
+			// zero width characters belong to the preceding character
+			if pos >= start && pos+w <= end && (w > 0 || pos > start || sb.Len() > 0) {
+				sb.WriteRune(r)
+			}
+			pos += w
+		}
+		result = result.Append(sb.String(), span.Style)
+		if pos >= end {
+			break
+		}
+	}
+	return result
+}
+
+// Split slices the text into all the parts separated by sep. The styles of the parts are preserved
+func (t StyledText) Split(sep string) []StyledText {
+	plain := t.String()
+	var parts []StyledText
+	offset := 0
+	for _, part := range strings.Split(plain, sep) {
+		parts = append(parts, t.sliceBytes(offset, offset+len(part)))
+		offset += len(part) + len(sep)
+	}
+	return parts
+}
+
+// sliceBytes returns the part of the text between byte offsets of the plain text
+func (t StyledText) sliceBytes(start, end int) StyledText {
+	var result StyledText
+	pos := 0
+	for _, span := range t.spans {
+		spanStart, spanEnd := max(start-pos, 0), min(end-pos, len(span.Text))
+		if spanStart < spanEnd {
+			result = result.Append(span.Text[spanStart:spanEnd], span.Style)
+		}
+		pos += len(span.Text)
+	}
+	return result
+}
+
+// Render adds the text to the AnsiBuffer's buffer, converting the colours to the buffer's profile
+func (t StyledText) Render(a *AnsiBuffer) *AnsiBuffer {
+	for _, span := range t.spans {
+		a.WithStyle(span.Style, span.Text)
+	}
+	return a
+}
+
+// String returns the text without styles
+func (t StyledText) String() string {
+	var sb strings.Builder
+	for _, span := range t.spans {
+		sb.WriteString(span.Text)
+	}
+	return sb.String()
+}
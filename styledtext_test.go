@@ -0,0 +1,62 @@
+package ansie
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseAnsi(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	text := ParseAnsi("plain \033[1;31mbold red\033[22m red\033[0m \033]0;title\a\033[38;5;100;48;2;1;2;3mx\033[38:2::4:5:6my\033[m")
+	g.Expect(text.Spans()).To(Equal([]TextSpan{
+		{"plain ", NewStyle()},
+		{"bold red", NewStyle().Attr(Bold).Fg(Red)},
+		{" red", NewStyle().Fg(Red)},
+		{" ", NewStyle()},
+		{"x", NewStyle().Fg(100).BgRgb(1, 2, 3)},
+		{"y", NewStyle().FgRgb(4, 5, 6).BgRgb(1, 2, 3)},
+	}))
+	g.Expect(text.String()).To(Equal("plain bold red red xy"))
+
+	g.Expect(ParseAnsi("\033[91;104mhi\033[39;49m").Spans()).To(Equal([]TextSpan{
+		{"hi", NewStyle().FgHi(Red).BgHi(Blue)},
+	}))
+}
+
+func TestStyledText_Render(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	s := "a\033[1;31mb\033[0mc\033[4md\033[0m"
+	g.Expect(ParseAnsi(s).Render(NewAnsi()).String()).To(Equal(s), "Expected parsed text to render to the same string")
+
+	a := NewAnsi()
+	a.SetProfile(Colours16)
+	g.Expect(NewStyledText("x", NewStyle().FgRgb(255, 0, 0)).Render(a).String()).To(Equal("\033[91mx\033[0m"))
+	a.SetEnabled(false)
+	g.Expect(NewStyledText("x", NewStyle().Fg(Red)).Render(a).String()).To(Equal("x"))
+}
+
+func TestStyledText_Editing(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	red, bold := NewStyle().Fg(Red), NewStyle().Attr(Bold)
+	text := NewStyledText("hello ", red).Append("wide 日本", bold)
+	g.Expect(text.Len()).To(Equal(15))
+	g.Expect(text.Append("", red).Spans()).To(HaveLen(2))
+	g.Expect(text.Append("!", bold).Spans()).To(HaveLen(2), "Expected span with the same style to be extended")
+
+	g.Expect(text.Slice(3, 8).Spans()).To(Equal([]TextSpan{{"lo ", red}, {"wi", bold}}))
+	g.Expect(text.Slice(12, 15).String()).To(Equal("本"), "Expected partially included wide character to be dropped")
+	g.Expect(text.Slice(0, 100).String()).To(Equal("hello wide 日本"))
+
+	parts := text.Split(" ")
+	g.Expect(parts).To(HaveLen(3))
+	g.Expect(parts[0].Spans()).To(Equal([]TextSpan{{"hello", red}}))
+	g.Expect(parts[2].Spans()).To(Equal([]TextSpan{{"日本", bold}}))
+
+	joined := parts[0].Concat(NewStyledText("-", NewStyle()), parts[1])
+	g.Expect(joined.Spans()).To(Equal([]TextSpan{{"hello", red}, {"-", NewStyle()}, {"wide", bold}}))
+	g.Expect(text.Spans()).To(HaveLen(2), "Expected the original text to be unchanged")
+}
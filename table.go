@@ -0,0 +1,285 @@
+package ansie
+
+import (
+	"strings"
+)
+
+const (
+	tableCellPadding = 1
+	// columns of tables without borders are separated with this string
+	tableColumnGap     = "  "
+	minMarkdownColumn  = 3
+	tableTruncatedTail = "…"
+)
+
+// Column defines the header and layout of the table column
+type Column struct {
+	Header string
+	Align  Alignment
+	// MinWidth and MaxWidth limit the width of the column content in cells. Zero MaxWidth means no limit
+	MinWidth int
+	MaxWidth int
+	// Wrap breaks the cells that are too wide into several lines instead of truncating them
+	Wrap bool
+}
+
+// Table renders rows of cells as a table. Widths of the cells are measured ignoring ANSI escape sequences,
+// so cells can contain styled text produced by AnsiBuffer. Cells can contain several lines separated with
+// line breaks.
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+	// Border defines the characters used to draw the table lines. The zero value is NoBorder
+	Border Border
+	// BorderStyle is the style of the table lines
+	BorderStyle Style
+	// HeaderStyle is the style of the header row
+	HeaderStyle Style
+	// RowStyle is the style of all rows except the header
+	RowStyle Style
+	// ZebraStyle is applied on top of RowStyle to every second row
+	ZebraStyle Style
+	// Width is the maximum width of the table including the borders. If the table is wider, columns are
+	// narrowed, widest first, but not below their MinWidth. Zero means no limit
+	Width int
+}
+
+// NewTable creates a table with single line borders and the columns with the given headers
+func NewTable(headers ...string) *Table {
+	columns := make([]Column, len(headers))
+	for i, header := range headers {
+		columns[i].Header = header
+	}
+	return &Table{Columns: columns, Border: SingleBorder}
+}
+
+// AddRow appends a row of cells to the table
+func (t *Table) AddRow(cells ...string) *Table {
+	t.Rows = append(t.Rows, cells)
+	return t
+}
+
+// Render adds the table to the AnsiBuffer's buffer. Every line except the last one is terminated with a line break.
+// The header row is rendered only if any of the columns has a header.
+// Markdown tables are always rendered with single-line rows, line breaks in the cells are replaced with spaces.
+func (t *Table) Render(a *AnsiBuffer) *AnsiBuffer {
+	columns := t.columns()
+	if len(columns) == 0 {
+		return a
+	}
+	hasHeader := false
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = t.cellText(col.Header)
+		hasHeader = hasHeader || col.Header != ""
+	}
+	rows := make([][]string, len(t.Rows))
+	for r, row := range t.Rows {
+		rows[r] = make([]string, len(columns))
+		for i := range row {
+			rows[r][i] = t.cellText(row[i])
+		}
+	}
+	widths := t.columnWidths(columns, headers, rows, hasHeader)
+
+	b := t.Border
+	lines := 0
+	newLine := func() {
+		if lines > 0 {
+			a.CR()
+		}
+		lines++
+	}
+	if !b.IsNone() && !b.markdown {
+		newLine()
+		t.renderLine(a, widths, b.TopLeft, b.TopTee, b.TopRight)
+	}
+	if hasHeader {
+		newLine()
+		t.renderRow(a, columns, widths, headers, t.HeaderStyle)
+		switch {
+		case b.markdown:
+			newLine()
+			t.renderMarkdownSeparator(a, columns, widths)
+		case !b.IsNone():
+			newLine()
+			t.renderLine(a, widths, b.LeftTee, b.Cross, b.RightTee)
+		}
+	}
+	for r, row := range rows {
+		style := t.RowStyle
+		if r%2 == 1 {
+			style = style.Merge(t.ZebraStyle)
+		}
+		newLine()
+		t.renderRow(a, columns, widths, row, style)
+	}
+	if !b.IsNone() && !b.markdown {
+		newLine()
+		t.renderLine(a, widths, b.BottomLeft, b.BottomTee, b.BottomRight)
+	}
+	return a
+}
+
+// String renders the table as a plain text without colours
+func (t *Table) String() string {
+	a := NewAnsi()
+	a.SetEnabled(false)
+	return t.Render(a).String()
+}
+
+// columns returns the column definitions for all the cells in the table, adding columns with default layout
+// for the rows that have more cells than there are columns defined
+func (t *Table) columns() []Column {
+	n := len(t.Columns)
+	for _, row := range t.Rows {
+		n = max(n, len(row))
+	}
+	columns := make([]Column, n)
+	copy(columns, t.Columns)
+	return columns
+}
+
+func (t *Table) cellText(cell string) string {
+	if t.Border.markdown {
+		cell = strings.ReplaceAll(cell, "\n", " ")
+		cell = strings.ReplaceAll(cell, "|", "\\|")
+	}
+	return cell
+}
+
+// overhead is the number of cells taken by the borders, padding and column gaps
+func (t *Table) overhead(columns int) int {
+	if t.Border.IsNone() {
+		return (columns - 1) * len(tableColumnGap)
+	}
+	return columns*(2*tableCellPadding+1) + 1
+}
+
+func (t *Table) columnWidths(columns []Column, headers []string, rows [][]string, hasHeader bool) []int {
+	widths := make([]int, len(columns))
+	minWidths := make([]int, len(columns))
+	for i, col := range columns {
+		cells := []string{}
+		if hasHeader {
+			cells = append(cells, headers[i])
+		}
+		for _, row := range rows {
+			cells = append(cells, row[i])
+		}
+		for _, cell := range cells {
+			for _, line := range strings.Split(cell, "\n") {
+				widths[i] = max(widths[i], StringWidth(line))
+			}
+		}
+		if col.MaxWidth > 0 {
+			widths[i] = min(widths[i], col.MaxWidth)
+		}
+		minWidths[i] = max(col.MinWidth, 1)
+		if t.Border.markdown {
+			minWidths[i] = max(minWidths[i], minMarkdownColumn)
+		}
+		widths[i] = max(widths[i], minWidths[i])
+	}
+	if t.Width <= 0 {
+		return widths
+	}
+	for excess := sum(widths) + t.overhead(len(columns)) - t.Width; excess > 0; excess-- {
+		widest := -1
+		for i, w := range widths {
+			if w > minWidths[i] && (widest < 0 || w > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// cellLines splits the cell into lines, wrapped or truncated to the width, and aligns them
+func (t *Table) cellLines(cell string, width int, col Column) []string {
+	var lines []string
+	if col.Wrap && !t.Border.markdown {
+		lines = Wrap(cell, width)
+	} else {
+		for _, line := range strings.Split(cell, "\n") {
+			lines = append(lines, Truncate(line, width, tableTruncatedTail))
+		}
+	}
+	for i, line := range lines {
+		lines[i] = Align(line, width, col.Align)
+	}
+	return lines
+}
+
+func (t *Table) renderRow(a *AnsiBuffer, columns []Column, widths []int, cells []string, style Style) {
+	cellLines := make([][]string, len(columns))
+	height := 1
+	for i, col := range columns {
+		cellLines[i] = t.cellLines(cells[i], widths[i], col)
+		height = max(height, len(cellLines[i]))
+	}
+	bordered := !t.Border.IsNone()
+	padding := strings.Repeat(" ", tableCellPadding)
+	for line := 0; line < height; line++ {
+		if line > 0 {
+			a.CR()
+		}
+		if bordered {
+			a.WithStyle(t.BorderStyle, t.Border.Vertical)
+		}
+		for i := range columns {
+			text := strings.Repeat(" ", widths[i])
+			if line < len(cellLines[i]) {
+				text = cellLines[i][line]
+			}
+			if bordered {
+				a.withNestedStyle(style, padding+text+padding)
+				a.WithStyle(t.BorderStyle, t.Border.Vertical)
+			} else {
+				if i > 0 {
+					text = tableColumnGap + text
+				}
+				a.withNestedStyle(style, text)
+			}
+		}
+	}
+}
+
+// renderLine draws a horizontal line of the table
+func (t *Table) renderLine(a *AnsiBuffer, widths []int, left, cross, right string) {
+	segments := make([]string, len(widths))
+	for i, w := range widths {
+		segments[i] = strings.Repeat(t.Border.Horizontal, w+2*tableCellPadding)
+	}
+	a.WithStyle(t.BorderStyle, left+strings.Join(segments, cross)+right)
+}
+
+// renderMarkdownSeparator draws the line between the header and the rows of the Markdown table, with the column
+// alignment markers
+func (t *Table) renderMarkdownSeparator(a *AnsiBuffer, columns []Column, widths []int) {
+	segments := make([]string, len(widths))
+	for i, w := range widths {
+		w += 2 * tableCellPadding
+		switch columns[i].Align {
+		case AlignCenter:
+			segments[i] = ":" + strings.Repeat("-", w-2) + ":"
+		case AlignRight:
+			segments[i] = strings.Repeat("-", w-1) + ":"
+		default:
+			segments[i] = strings.Repeat("-", w)
+		}
+	}
+	a.WithStyle(t.BorderStyle, "|"+strings.Join(segments, "|")+"|")
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
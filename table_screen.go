@@ -0,0 +1,9 @@
+//go:build !windows
+
+package ansie
+
+// FitScreen limits the width of the table to the width of the screen
+func (t *Table) FitScreen(s *Screen) *Table {
+	t.Width, _ = s.Size()
+	return t
+}
@@ -0,0 +1,88 @@
+package ansie
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTable_Borders(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tb := NewTable("Name", "Qty")
+	tb.Columns[1].Align = AlignRight
+	tb.AddRow("apple", "3").AddRow("日本", "12")
+	g.Expect(tb.String()).To(Equal(strings.Join([]string{
+		"┌───────┬─────┐",
+		"│ Name  │ Qty │",
+		"├───────┼─────┤",
+		"│ apple │   3 │",
+		"│ 日本  │  12 │",
+		"└───────┴─────┘",
+	}, "\n")))
+
+	tb.Border = AsciiBorder
+	g.Expect(strings.Split(tb.String(), "\n")[0]).To(Equal("+-------+-----+"))
+
+	tb.Border = NoBorder
+	g.Expect(tb.String()).To(Equal("Name   Qty\napple    3\n日本    12"))
+
+	tb.Border = MarkdownBorder
+	tb.AddRow("a|b", "1")
+	g.Expect(tb.String()).To(Equal(strings.Join([]string{
+		"| Name  | Qty |",
+		"|-------|----:|",
+		"| apple |   3 |",
+		"| 日本  |  12 |",
+		"| a\\|b  |   1 |",
+	}, "\n")))
+}
+
+func TestTable_AnsiCells(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tb := &Table{Border: RoundedBorder}
+	tb.AddRow(NewAnsi().Fg(Red).A("red").Reset().String(), "x")
+	g.Expect(tb.Render(NewAnsi()).String()).To(Equal(strings.Join([]string{
+		"╭─────┬───╮",
+		"│ \033[31mred\033[0m │ x │",
+		"╰─────┴───╯",
+	}, "\n")))
+}
+
+func TestTable_Width(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tb := &Table{Columns: []Column{{}, {Wrap: true}}, Width: 12}
+	tb.AddRow("truncated", "wrapped text")
+	g.Expect(tb.String()).To(Equal("trun…  wrapp\n       ed   \n       text "))
+
+	tb.Columns[0].MinWidth = 9
+	g.Expect(strings.Split(tb.String(), "\n")[0]).To(Equal("truncated  w"))
+
+	tb.Columns[0].MinWidth = 0
+	tb.Columns[0].MaxWidth = 3
+	tb.Width = 0
+	g.Expect(tb.String()).To(Equal("tr…  wrapped text"))
+}
+
+func TestTable_Styles(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tb := &Table{
+		Columns:     []Column{{Header: "h"}},
+		Border:      AsciiBorder,
+		BorderStyle: NewStyle().Fg(Blue),
+		HeaderStyle: NewStyle().Attr(Bold),
+		ZebraStyle:  NewStyle().Bg(White),
+	}
+	tb.AddRow("a").AddRow(NewAnsi().Fg(Red).A("b").Reset().String())
+	lines := strings.Split(tb.Render(NewAnsi()).String(), "\n")
+	g.Expect(lines).To(HaveLen(6))
+	g.Expect(lines[0]).To(Equal("\033[34m+---+\033[0m"))
+	g.Expect(lines[1]).To(Equal("\033[34m|\033[0m\033[1m h \033[0m\033[34m|\033[0m"))
+	g.Expect(lines[3]).To(Equal("\033[34m|\033[0m a \033[34m|\033[0m"))
+	g.Expect(lines[4]).To(Equal("\033[34m|\033[0m\033[47m \033[31mb\033[0m\033[47m \033[0m\033[34m|\033[0m"),
+		"Expected zebra style to be restored after the styled cell content")
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package ansie
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminalWidth returns the width of the terminal connected to f, it returns false if f is not a terminal
+func terminalWidth(f *os.File) (int, bool) {
+	winSize, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil || winSize.Col == 0 {
+		return 0, false
+	}
+	return int(winSize.Col), true
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package ansie
+
+import "os"
+
+// terminalWidth returns the width of the terminal connected to f. The size of Windows console is not detected
+func terminalWidth(*os.File) (int, bool) {
+	return 0, false
+}
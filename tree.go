@@ -0,0 +1,155 @@
+package ansie
+
+import "strings"
+
+// TreeGuides are the strings drawn in front of the tree nodes to show their hierarchy.
+// All the guides should have the same width.
+type TreeGuides struct {
+	// Branch is drawn in front of every node, except the last child of its parent
+	Branch string
+	// Last is drawn in front of the last child
+	Last string
+	// Vertical continues the line of the parent node past its children
+	Vertical string
+	// Space is drawn under the last children, where there is no line to continue
+	Space string
+}
+
+var (
+	// UnicodeGuides are drawn with box-drawing characters: ├── └── │
+	UnicodeGuides = TreeGuides{Branch: "├── ", Last: "└── ", Vertical: "│   ", Space: "    "}
+	// RoundedGuides are drawn with box-drawing characters and rounded corners: ├── ╰── │
+	RoundedGuides = TreeGuides{Branch: "├── ", Last: "╰── ", Vertical: "│   ", Space: "    "}
+	// AsciiGuides use only ASCII characters: |-- `--
+	AsciiGuides = TreeGuides{Branch: "|-- ", Last: "`-- ", Vertical: "|   ", Space: "    "}
+)
+
+const defaultCollapsedMarker = "…"
+
+// TreeNode is a node of the Tree with a label and child nodes
+type TreeNode struct {
+	Label    string
+	Style    Style
+	Children []*TreeNode
+	// Collapsed hides the children of the node
+	Collapsed bool
+}
+
+// NewTreeNode creates a node with the label and the child nodes
+func NewTreeNode(label string, children ...*TreeNode) *TreeNode {
+	return &TreeNode{Label: label, Children: children}
+}
+
+// Add adds a new child node with the label and returns it
+func (n *TreeNode) Add(label string) *TreeNode {
+	child := NewTreeNode(label)
+	n.Children = append(n.Children, child)
+	return child
+}
+
+// WithStyle sets the style of the node label and returns the node
+func (n *TreeNode) WithStyle(style Style) *TreeNode {
+	n.Style = style
+	return n
+}
+
+// Tree renders hierarchical data, like file or dependency listings, similar to the output of `tree` utility.
+// Labels can contain ANSI escape sequences and line breaks.
+type Tree struct {
+	// Root is drawn on the first line without guides. If Root has an empty label, only its children are drawn
+	// and Collapsed flag of the root is ignored
+	Root *TreeNode
+	// Guides default to UnicodeGuides
+	Guides *TreeGuides
+	// GuideStyle is the style of the guides
+	GuideStyle Style
+	// MaxDepth limits the depth of the drawn nodes, children of the nodes at MaxDepth are collapsed.
+	// Children of the root have depth 1. Zero means no limit
+	MaxDepth int
+	// CollapsedMarker is appended to the labels of the collapsed nodes that have children. Defaults to "…"
+	CollapsedMarker string
+	// Width limits the width of the tree. Labels that don't fit are wrapped, the continuation lines are
+	// indented to the start of the label. Zero means no limit
+	Width int
+}
+
+// Render adds the tree to the AnsiBuffer's buffer. Every line except the last one is terminated with a line break.
+func (t *Tree) Render(a *AnsiBuffer) *AnsiBuffer {
+	if t.Root == nil {
+		return a
+	}
+	r := &treeRenderer{tree: t, a: a, guides: UnicodeGuides, marker: t.CollapsedMarker}
+	if t.Guides != nil {
+		r.guides = *t.Guides
+	}
+	if r.marker == "" {
+		r.marker = defaultCollapsedMarker
+	}
+	if t.Root.Label != "" {
+		r.node(t.Root, 0, "", "", "")
+	}
+	// the root without label is not drawn, so it can't be collapsed
+	if t.Root.Label == "" || r.expanded(t.Root, 0) {
+		r.children(t.Root, 1, "")
+	}
+	return a
+}
+
+// String renders the tree as a plain text without colours
+func (t *Tree) String() string {
+	a := NewAnsi()
+	a.SetEnabled(false)
+	return t.Render(a).String()
+}
+
+type treeRenderer struct {
+	tree   *Tree
+	a      *AnsiBuffer
+	guides TreeGuides
+	marker string
+	lines  int
+}
+
+func (r *treeRenderer) children(parent *TreeNode, depth int, prefix string) {
+	for i, child := range parent.Children {
+		guide, continuation := r.guides.Branch, r.guides.Vertical
+		if i == len(parent.Children)-1 {
+			guide, continuation = r.guides.Last, r.guides.Space
+		}
+		r.node(child, depth, prefix, guide, continuation)
+		if r.expanded(child, depth) {
+			r.children(child, depth+1, prefix+continuation)
+		}
+	}
+}
+
+// node draws the label of the node. The first line of the label is preceded with prefix and guide,
+// the continuation lines with prefix and continuation
+func (r *treeRenderer) node(n *TreeNode, depth int, prefix, guide, continuation string) {
+	label := n.Label
+	if len(n.Children) > 0 && !r.expanded(n, depth) {
+		label += " " + r.marker
+	}
+	var lines []string
+	if r.tree.Width > 0 {
+		lines = Wrap(label, r.tree.Width-StringWidth(prefix+guide))
+	} else {
+		lines = carrySgr(strings.Split(label, "\n"))
+	}
+	for i, line := range lines {
+		if r.lines > 0 {
+			r.a.CR()
+		}
+		r.lines++
+		if i == 0 {
+			r.a.WithStyle(r.tree.GuideStyle, prefix+guide)
+		} else {
+			r.a.WithStyle(r.tree.GuideStyle, prefix+continuation)
+		}
+		r.a.WithStyle(n.Style, line)
+	}
+}
+
+func (r *treeRenderer) expanded(n *TreeNode, depth int) bool {
+	return !n.Collapsed && (r.tree.MaxDepth <= 0 || depth < r.tree.MaxDepth)
+}
@@ -0,0 +1,94 @@
+package ansie
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func testTree() *TreeNode {
+	root := NewTreeNode(".")
+	src := root.Add("src")
+	src.Add("main.go")
+	src.Add("util").Add("strings.go")
+	root.Add("readme.md")
+	return root
+}
+
+func TestTree_Render(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tree := &Tree{Root: testTree()}
+	g.Expect(tree.String()).To(Equal(strings.Join([]string{
+		".",
+		"├── src",
+		"│   ├── main.go",
+		"│   └── util",
+		"│       └── strings.go",
+		"└── readme.md",
+	}, "\n")))
+
+	tree.Guides = &AsciiGuides
+	tree.Root.Label = ""
+	g.Expect(tree.String()).To(Equal(strings.Join([]string{
+		"|-- src",
+		"|   |-- main.go",
+		"|   `-- util",
+		"|       `-- strings.go",
+		"`-- readme.md",
+	}, "\n")))
+}
+
+func TestTree_Collapse(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tree := &Tree{Root: testTree(), MaxDepth: 1}
+	g.Expect(tree.String()).To(Equal(".\n├── src …\n└── readme.md"))
+
+	tree.MaxDepth = 0
+	tree.CollapsedMarker = "[+]"
+	tree.Root.Children[0].Children[1].Collapsed = true
+	g.Expect(tree.String()).To(ContainSubstring("│   └── util [+]\n└── readme.md"))
+}
+
+func TestTree_CollapsedRoot(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tree := &Tree{Root: testTree()}
+	tree.Root.Collapsed = true
+	g.Expect(tree.String()).To(Equal(". …"), "Expected children of the collapsed root to be hidden")
+
+	tree.Root.Label = ""
+	g.Expect(tree.String()).To(HavePrefix("├── src"), "Expected unlabelled root to show its children")
+}
+
+func TestTree_Wrap(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	root := NewTreeNode("")
+	node := root.Add("a long label")
+	node.Add("child")
+	root.Add("last long label")
+	tree := &Tree{Root: root, Width: 10}
+	g.Expect(tree.String()).To(Equal(strings.Join([]string{
+		"├── a long",
+		"│   label",
+		"│   └── ch",
+		"│       il",
+		"│       d",
+		"└── last",
+		"    long",
+		"    label",
+	}, "\n")))
+}
+
+func TestTree_Styles(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	root := NewTreeNode("")
+	root.Add("dir").WithStyle(NewStyle().Fg(Blue)).Add("file")
+	tree := &Tree{Root: root, GuideStyle: NewStyle().Attr(Faint)}
+	g.Expect(tree.Render(NewAnsi()).String()).To(Equal(
+		"\033[2m└── \033[0m\033[34mdir\033[0m\n\033[2m    └── \033[0mfile"))
+}
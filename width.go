@@ -0,0 +1,292 @@
+package ansie
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// Alignment defines horizontal alignment of the text
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+	AlignRight
+)
+
+// RuneWidth returns the number of terminal cells the rune occupies: 0 for control characters and combining marks,
+// 2 for East Asian wide and full-width characters and 1 for everything else
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r >= 0x1160 && r <= 0x11ff: // Hangul Jamo medial vowels and final consonants
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// StringWidth returns the number of terminal cells the visible text of s occupies. ANSI escape sequences
+// are not counted.
+func StringWidth(s string) int {
+	w := 0
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w += RuneWidth(r)
+		i += size
+	}
+	return w
+}
+
+// StripAnsi removes all ANSI escape sequences from s
+func StripAnsi(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		sb.WriteRune(r)
+		i += size
+	}
+	return sb.String()
+}
+
+// Truncate shortens s so that its visible text occupies at most width cells. If the text is truncated, the tail,
+// for example "…", is appended to it, and the result including the tail fits into width.
+// Escape sequences are preserved, including those in the removed part of the text, so that the colours and
+// attributes reset at the end of s still apply.
+func Truncate(s string, width int, tail string) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+	target := width - StringWidth(tail)
+	if target < 0 {
+		return Truncate(tail, width, "")
+	}
+	var sb strings.Builder
+	var escapes strings.Builder
+	w := 0
+	truncated := false
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			if truncated {
+				escapes.WriteString(s[i : i+n])
+			} else {
+				sb.WriteString(s[i : i+n])
+			}
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if truncated {
+			continue
+		}
+		rw := RuneWidth(r)
+		if w+rw > target {
+			truncated = true
+			sb.WriteString(tail)
+			continue
+		}
+		sb.WriteRune(r)
+		w += rw
+	}
+	sb.WriteString(escapes.String())
+	return sb.String()
+}
+
+// Align pads s with spaces to the width cells according to the alignment. Text wider than width is not truncated.
+func Align(s string, width int, alignment Alignment) string {
+	padding := width - StringWidth(s)
+	if padding <= 0 {
+		return s
+	}
+	switch alignment {
+	case AlignRight:
+		return strings.Repeat(" ", padding) + s
+	case AlignCenter:
+		left := padding / 2
+		return strings.Repeat(" ", left) + s + strings.Repeat(" ", padding-left)
+	default:
+		return s + strings.Repeat(" ", padding)
+	}
+}
+
+// escapeLength returns the length of the escape sequence at the beginning of s or 0 if s doesn't start with one.
+// It recognises CSI sequences, string sequences (OSC, DCS, APC, PM and SOS) and two-character escapes.
+// Unterminated sequences extend to the end of s.
+func escapeLength(s string) int {
+	if len(s) == 0 || s[0] != '\033' {
+		return 0
+	}
+	if len(s) == 1 {
+		return 1
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']', 'P', '_', '^', 'X':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' && s[1] == ']' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	default:
+		i := 1
+		for i < len(s)-1 && s[i] >= 0x20 && s[i] <= 0x2f {
+			i++
+		}
+		return i + 1
+	}
+}
+
+// Wrap breaks s into lines that occupy at most width cells. Lines are broken at spaces, words longer than width
+// are broken at any character. Lines that already fit are left intact, in lines that are wrapped runs of spaces
+// are collapsed. Existing line breaks are preserved.
+// Colours and attributes set by SGR sequences continue on the next line after the break: every wrapped line that
+// ends with SGR attributes in effect is terminated with reset and the next line starts with the active sequences.
+func Wrap(s string, width int) []string {
+	width = max(width, 1)
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		if StringWidth(paragraph) <= width {
+			lines = append(lines, paragraph)
+		} else {
+			lines = append(lines, wrapParagraph(paragraph, width)...)
+		}
+	}
+	return carrySgr(lines)
+}
+
+type wrapWord struct {
+	text  string
+	width int
+}
+
+func wrapParagraph(s string, width int) []string {
+	var words []wrapWord
+	var word strings.Builder
+	wordWidth := 0
+	endWord := func() {
+		switch {
+		case wordWidth > 0:
+			words = append(words, wrapWord{text: word.String(), width: wordWidth})
+		case word.Len() > 0 && len(words) > 0:
+			// escape sequences between the words are attached to the previous word
+			words[len(words)-1].text += word.String()
+		default:
+			return // sequences before the first word stay in the builder and start the next word
+		}
+		word.Reset()
+		wordWidth = 0
+	}
+	for i := 0; i < len(s); {
+		if n := escapeLength(s[i:]); n > 0 {
+			word.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if r == ' ' {
+			endWord()
+			continue
+		}
+		word.WriteRune(r)
+		wordWidth += RuneWidth(r)
+	}
+	endWord()
+
+	var lines []string
+	var line strings.Builder
+	lineWidth := 0
+	for _, w := range words {
+		if lineWidth > 0 && lineWidth+1+w.width > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+		}
+		if lineWidth > 0 {
+			line.WriteByte(' ')
+			lineWidth++
+		}
+		if w.width <= width {
+			line.WriteString(w.text)
+			lineWidth += w.width
+			continue
+		}
+		for i := 0; i < len(w.text); {
+			if n := escapeLength(w.text[i:]); n > 0 {
+				line.WriteString(w.text[i : i+n])
+				i += n
+				continue
+			}
+			r, size := utf8.DecodeRuneInString(w.text[i:])
+			i += size
+			rw := RuneWidth(r)
+			if lineWidth > 0 && lineWidth+rw > width {
+				lines = append(lines, line.String())
+				line.Reset()
+				lineWidth = 0
+			}
+			line.WriteRune(r)
+			lineWidth += rw
+		}
+	}
+	return append(lines, line.String())
+}
+
+// carrySgr makes every line self-contained by repeating the SGR sequences that are in effect at the end of the
+// previous line at the start of the next one and resetting them at the end of the line
+func carrySgr(lines []string) []string {
+	var active []string
+	for i, line := range lines {
+		prefix := strings.Join(active, "")
+		for j := 0; j < len(line); {
+			n := escapeLength(line[j:])
+			if n == 0 {
+				j++
+				continue
+			}
+			seq := line[j : j+n]
+			if strings.HasPrefix(seq, esc) && strings.HasSuffix(seq, "m") {
+				if seq == esc+"m" || seq == esc+"0m" {
+					active = active[:0]
+				} else {
+					active = append(active, seq)
+				}
+			}
+			j += n
+		}
+		if len(active) > 0 && i < len(lines)-1 {
+			line += esc + "0m"
+		}
+		lines[i] = prefix + line
+	}
+	return lines
+}
@@ -0,0 +1,84 @@
+package ansie
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRuneWidth(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(RuneWidth('a')).To(Equal(1))
+	g.Expect(RuneWidth('█')).To(Equal(1))
+	g.Expect(RuneWidth('日')).To(Equal(2))
+	g.Expect(RuneWidth('Ａ')).To(Equal(2))
+	g.Expect(RuneWidth('🚀')).To(Equal(2))
+	g.Expect(RuneWidth('́')).To(Equal(0), "Expected combining mark to have zero width")
+	g.Expect(RuneWidth('‍')).To(Equal(0), "Expected zero width joiner to have zero width")
+	g.Expect(RuneWidth('\t')).To(Equal(0))
+}
+
+func TestStringWidth(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(StringWidth("hello")).To(Equal(5))
+	g.Expect(StringWidth(NewAnsi().Fg(Red).A("hello").Reset().String())).To(Equal(5))
+	g.Expect(StringWidth("\033]8;;http://example.com\033\\link\033]8;;\033\\")).To(Equal(4))
+	g.Expect(StringWidth("\033]0;title\adone")).To(Equal(4))
+	g.Expect(StringWidth("日本語")).To(Equal(6))
+	g.Expect(StringWidth("é")).To(Equal(1))
+}
+
+func TestStripAnsi(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(StripAnsi(NewAnsi().Fg(Red).A("red").Reset().A(" plain").String())).To(Equal("red plain"))
+	g.Expect(StripAnsi("\033(Bcharset\033[")).To(Equal("charset"))
+}
+
+func TestTruncate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Truncate("hello", 5, "…")).To(Equal("hello"))
+	g.Expect(Truncate("hello world", 8, "…")).To(Equal("hello w…"))
+	g.Expect(Truncate("hello world", 8, "")).To(Equal("hello wo"))
+	g.Expect(Truncate("日本語", 5, "…")).To(Equal("日本…"))
+	g.Expect(Truncate("日本語", 4, "")).To(Equal("日本"))
+	g.Expect(Truncate("\033[31mhello\033[0m", 3, "…")).To(Equal("\033[31mhe…\033[0m"))
+	g.Expect(Truncate("hello", 1, "...")).To(Equal("."))
+}
+
+func TestAlign(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Align("ab", 5, AlignLeft)).To(Equal("ab   "))
+	g.Expect(Align("ab", 5, AlignRight)).To(Equal("   ab"))
+	g.Expect(Align("ab", 5, AlignCenter)).To(Equal(" ab  "))
+	g.Expect(Align("\033[1mab\033[0m", 3, AlignRight)).To(Equal(" \033[1mab\033[0m"))
+	g.Expect(Align("abcdef", 3, AlignLeft)).To(Equal("abcdef"))
+}
+
+func TestWrap(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Wrap("short", 10)).To(Equal([]string{"short"}))
+	g.Expect(Wrap("the quick brown fox", 10)).To(Equal([]string{"the quick", "brown fox"}))
+	g.Expect(Wrap("one  two\nthree", 5)).To(Equal([]string{"one", "two", "three"}))
+	g.Expect(Wrap("abcdefgh ij", 3)).To(Equal([]string{"abc", "def", "gh", "ij"}))
+	g.Expect(Wrap("日本語", 4)).To(Equal([]string{"日本", "語"}))
+}
+
+func TestWrap_Styles(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Wrap("\033[31mred text\033[0m plain", 5)).To(Equal([]string{
+		"\033[31mred\033[0m",
+		"\033[31mtext\033[0m",
+		"plain",
+	}))
+	g.Expect(Wrap("\033[1mbold\033[0m \033[4mline\033[0m", 4)).To(Equal([]string{
+		"\033[1mbold\033[0m",
+		"\033[4mline\033[0m",
+	}))
+}